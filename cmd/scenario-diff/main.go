@@ -0,0 +1,66 @@
+// Command scenario-diff shows what would change if a rendered scenario was re-applied to the cluster
+//
+// Usage:
+//
+//	go run ./cmd/scenario-diff -f scenarios/rhtas/default/rhtas-default-base-scenario.yaml
+//	go run ./cmd/scenario-diff -f manifests.yaml -n rhtas -allow-cluster-scoped
+//
+// Documents are scoped like the installer does: -n sets the namespace of namespaced documents without one,
+// and documents of cluster-scoped kinds are refused unless -allow-cluster-scoped is set
+// The exit code is 0 when the cluster matches the scenario, 1 when there are differences and 2 on error
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/petrpinkas/config-examples/pkg/installer"
+	"github.com/petrpinkas/config-examples/pkg/kubernetes"
+)
+
+func main() {
+	filePath := flag.String("f", "", "path to a rendered scenario YAML file (required)")
+	full := flag.Bool("full", false, "include status, server metadata and operator-defaulted fields in the diff")
+	namespace := flag.String("n", "", "target namespace for namespaced documents without a namespace")
+	clusterScoped := flag.Bool("allow-cluster-scoped", false, "compare documents of cluster-scoped kinds, e.g. CRDs and ClusterRoles")
+	flag.Parse()
+
+	if *filePath == "" {
+		fmt.Fprintln(os.Stderr, "error: -f is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cli, err := kubernetes.GetClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: failed to create Kubernetes client: %v\n", err)
+		os.Exit(2)
+	}
+
+	var opts []installer.Option
+	if !*full {
+		opts = append(opts, installer.WithIgnoreDefaults())
+	}
+	if *namespace != "" {
+		opts = append(opts, installer.WithNamespace(*namespace))
+	}
+	if *clusterScoped {
+		opts = append(opts, installer.WithClusterScoped())
+	}
+
+	diffs, err := installer.DiffConfig(context.Background(), cli, nil, *filePath, opts...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(2)
+	}
+
+	report := installer.FormatDiffs(diffs)
+	if report == "" {
+		fmt.Println("No differences found")
+		return
+	}
+	fmt.Print(report)
+	os.Exit(1)
+}
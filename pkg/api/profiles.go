@@ -0,0 +1,90 @@
+package api
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Profile is a named set of values for one target cluster, defined under profiles in a YAML or JSON SETTINGS_FILE:
+//
+//	profiles:
+//	  qe-a:
+//	    kubeconfig: /home/jdoe/.kube/qe-a
+//	    context: admin@qe-a
+//	    values:
+//	      SIGSTORE_OIDC_ISSUER: https://keycloak.qe-a.example.com/auth/realms/trusted-artifact-signer
+//	      OIDC_USER: jdoe
+//	    conf:
+//	      Issuer: https://keycloak.qe-a.example.com/auth/realms/trusted-artifact-signer
+type Profile struct {
+	Name string `json:"-"`
+	// Kubeconfig is the kubeconfig file of the cluster, KUBECONFIG or ~/.kube/config if empty
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+	// Context is the kubeconfig context of the cluster, the current context if empty
+	Context string `json:"context,omitempty"`
+	// Values are api values such as service URLs and OIDC settings
+	Values map[string]string `json:"values,omitempty"`
+	// Conf overrides values of the scenario .conf files, e.g. the Issuer of Fulcio
+	Conf map[string]string `json:"conf,omitempty"`
+}
+
+// selectedProfile is the profile applied by the last Load
+var selectedProfile *Profile
+
+// SelectedProfile returns the profile selected by RHTAS_PROFILE (ProfileName), nil if none is selected
+func SelectedProfile() *Profile {
+	return selectedProfile
+}
+
+// LoadProfile reads the named profile from a YAML or JSON file
+func LoadProfile(file, name string) (*Profile, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profiles: %w", err)
+	}
+	var content struct {
+		Profiles map[string]*Profile `json:"profiles"`
+	}
+	if err := yaml.Unmarshal(data, &content); err != nil {
+		return nil, fmt.Errorf("failed to parse profiles in %s: %w", file, err)
+	}
+	profile := content.Profiles[name]
+	if profile == nil {
+		names := make([]string, 0, len(content.Profiles))
+		for n := range content.Profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("profile %q not found in %s, available: %s", name, file, strings.Join(names, ", "))
+	}
+	profile.Name = name
+	return profile, nil
+}
+
+// selectProfile loads the named profile and merges its values into Values, or deselects the profile if name is empty
+func selectProfile(file, name string) error {
+	selectedProfile = nil
+	if name == "" {
+		return nil
+	}
+	if file == "" {
+		return fmt.Errorf("%s %s needs a %s with profiles", ProfileName, name, SettingsFile)
+	}
+	profile, err := LoadProfile(file, name)
+	if err != nil {
+		return err
+	}
+	values := make(map[string]interface{}, len(profile.Values))
+	for key, value := range profile.Values {
+		values[key] = value
+	}
+	if err := Values.MergeConfigMap(values); err != nil {
+		return fmt.Errorf("failed to apply profile %s: %w", name, err)
+	}
+	selectedProfile = profile
+	return nil
+}
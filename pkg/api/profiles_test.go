@@ -0,0 +1,90 @@
+package api
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const profilesFile = `SIGSTORE_REKOR_URL: https://rekor.file.example.com
+OIDC_USER: jdoe
+profiles:
+  qe-a:
+    kubeconfig: /home/jdoe/.kube/qe-a
+    context: admin@qe-a
+    values:
+      SIGSTORE_OIDC_ISSUER: https://keycloak.qe-a.example.com/auth/realms/trusted-artifact-signer
+      SIGSTORE_REKOR_URL: https://rekor.qe-a.example.com
+      OIDC_USER: qe-user
+    conf:
+      Issuer: https://keycloak.qe-a.example.com/auth/realms/trusted-artifact-signer
+  qe-b:
+    context: admin@qe-b
+`
+
+var _ = Describe("Profiles", func() {
+	var file string
+
+	BeforeEach(func() {
+		os.Clearenv()
+		Values = viper.New()
+		Values.AutomaticEnv()
+		DeferCleanup(func() { selectedProfile = nil })
+
+		file = filepath.Join(GinkgoT().TempDir(), "settings.yaml")
+		Expect(os.WriteFile(file, []byte(profilesFile), 0o644)).To(Succeed())
+		_ = os.Setenv(SettingsFile, file)
+	})
+
+	It("should apply the profile selected in the environment", func() {
+		_ = os.Setenv(ProfileName, "qe-a")
+		_ = os.Setenv(OidcUser, "env-user")
+
+		Expect(Load(nil)).To(Succeed())
+		profile := SelectedProfile()
+		Expect(profile).NotTo(BeNil())
+		Expect(profile.Name).To(Equal("qe-a"))
+		Expect(profile.Kubeconfig).To(Equal("/home/jdoe/.kube/qe-a"))
+		Expect(profile.Context).To(Equal("admin@qe-a"))
+		// Conf keys keep their case, they are matched against template fields
+		Expect(profile.Conf).To(HaveKeyWithValue("Issuer", "https://keycloak.qe-a.example.com/auth/realms/trusted-artifact-signer"))
+
+		// The profile overrides the file, the environment overrides the profile
+		Expect(GetValueFor(RekorURL)).To(Equal("https://rekor.qe-a.example.com"))
+		Expect(GetValueFor(OidcIssuerURL)).To(Equal("https://keycloak.qe-a.example.com/auth/realms/trusted-artifact-signer"))
+		Expect(GetValueFor(OidcUser)).To(Equal("env-user"))
+	})
+
+	It("should select a profile with a flag", func() {
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		RegisterFlags(flags)
+		Expect(flags.Parse([]string{"--rhtas-profile=qe-b"})).To(Succeed())
+
+		Expect(Load(flags)).To(Succeed())
+		Expect(SelectedProfile().Context).To(Equal("admin@qe-b"))
+		Expect(GetValueFor(RekorURL)).To(Equal("https://rekor.file.example.com"))
+
+		settings, err := CurrentSettings()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(settings.Profile).To(Equal("qe-b"))
+	})
+
+	It("should use no profile unless one is selected", func() {
+		Expect(Load(nil)).To(Succeed())
+		Expect(SelectedProfile()).To(BeNil())
+		Expect(GetValueFor(OidcUser)).To(Equal("jdoe"))
+	})
+
+	It("should report unknown profiles and a missing file", func() {
+		_ = os.Setenv(ProfileName, "qe-c")
+		Expect(Load(nil)).To(MatchError(ContainSubstring(`profile "qe-c" not found in ` + file + ", available: qe-a, qe-b")))
+		Expect(SelectedProfile()).To(BeNil())
+
+		_ = os.Unsetenv(SettingsFile)
+		Expect(Load(nil)).To(MatchError("RHTAS_PROFILE qe-c needs a SETTINGS_FILE with profiles"))
+	})
+})
@@ -0,0 +1,271 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/petrpinkas/config-examples/pkg/secrets"
+	"github.com/spf13/pflag"
+)
+
+// redacted replaces secrets in Settings.String
+const redacted = "<redacted>"
+
+// setting describes a value Settings is loaded from
+type setting struct {
+	key   string
+	usage string
+	// secret values are redacted by Settings.String
+	secret bool
+}
+
+// settings are all values of Settings in the order String prints them
+var settings = []setting{
+	{key: FulcioURL, usage: "Fulcio URL (required)"},
+	{key: RekorURL, usage: "Rekor URL (required)"},
+	{key: TufURL, usage: "TUF repository URL (required)"},
+	{key: OidcIssuerURL, usage: "OIDC issuer URL (required)"},
+	{key: TsaURL, usage: "timestamp authority URL"},
+	{key: OidcToken, usage: "OIDC identity token, retrieved with the password grant if not set", secret: true},
+	{key: OidcUser, usage: "OIDC user for the password grant"},
+	{key: OidcPassword, usage: "OIDC password for the password grant", secret: true},
+	{key: OidcUserDomain, usage: "email domain of the OIDC user"},
+	{key: OidcRealm, usage: "Keycloak realm appended to an issuer without realm"},
+	{key: OidcClientID, usage: "OIDC client ID"},
+	{key: CosignBinary, usage: "cosign binary, looked up in PATH if not set"},
+	{key: CLITimeout, usage: "timeout of a single CLI command, e.g. 2m"},
+	{key: ManualImageSetup, usage: "use TARGET_IMAGE_NAME instead of preparing an image"},
+	{key: TargetImageName, usage: "image to sign (required if MANUAL_IMAGE_SETUP is true)"},
+	{key: ProfileName, usage: "profile of the settings file to use"},
+}
+
+// Settings are the typed and validated configuration values
+type Settings struct {
+	FulcioURL     *url.URL
+	RekorURL      *url.URL
+	TufURL        *url.URL
+	OidcIssuerURL *url.URL
+	// TsaURL is nil if no TSA is used
+	TsaURL *url.URL
+
+	OidcToken      string
+	OidcUser       string
+	OidcPassword   string
+	OidcUserDomain string
+	OidcRealm      string
+	OidcClientID   string
+
+	CosignBinary string
+	// CLITimeout is zero if not set, the CLI default applies then
+	CLITimeout time.Duration
+
+	ManualImageSetup bool
+	TargetImageName  string
+
+	// Profile is the name of the selected profile, empty if none is selected
+	Profile string
+}
+
+// FlagName returns the command line flag of a value, e.g. --sigstore-fulcio-url for SIGSTORE_FULCIO_URL
+func FlagName(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", "-"))
+}
+
+// RegisterFlags adds a flag for every value of Settings and for SETTINGS_FILE to fs
+func RegisterFlags(fs *pflag.FlagSet) {
+	fs.String(FlagName(SettingsFile), "", "YAML, JSON or env file with configuration values")
+	for _, s := range settings {
+		fs.String(FlagName(s.key), "", s.usage)
+	}
+}
+
+// Load reads the SETTINGS_FILE and the selected profile into Values
+// Flags registered with RegisterFlags take precedence over the environment, the environment over the
+// profile, the profile over the rest of the SETTINGS_FILE and the file over the defaults; flags is optional
+func Load(flags *pflag.FlagSet) error {
+	if flags != nil {
+		for _, key := range append([]string{SettingsFile}, settingKeys()...) {
+			if flag := flags.Lookup(FlagName(key)); flag != nil {
+				if err := Values.BindPFlag(key, flag); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	file := GetValueFor(SettingsFile)
+	if file != "" {
+		Values.SetConfigFile(file)
+		if err := Values.ReadInConfig(); err != nil {
+			return fmt.Errorf("failed to read %s %s: %w", SettingsFile, file, err)
+		}
+	}
+	return selectProfile(file, GetValueFor(ProfileName))
+}
+
+// ResolveSecrets replaces values that are secret references, e.g. OIDC_PASSWORD=secret://env/KEYCLOAK_PASSWORD,
+// with the secrets they point to
+// Sources other than file and env, such as k8s, have to be registered with secrets.Register before
+func ResolveSecrets(ctx context.Context) error {
+	values := make(map[string]string)
+	for _, key := range settingKeys() {
+		if value := GetValueFor(key); secrets.IsReference(value) {
+			values[key] = value
+		}
+	}
+	if err := secrets.ResolveAll(ctx, values); err != nil {
+		return err
+	}
+	for key, value := range values {
+		Values.Set(key, value)
+	}
+	return nil
+}
+
+// LoadSettings calls Load and ResolveSecrets and returns the parsed and validated Settings
+// All invalid and missing values are reported in one error
+func LoadSettings(ctx context.Context, flags *pflag.FlagSet) (*Settings, error) {
+	if err := Load(flags); err != nil {
+		return nil, err
+	}
+	if err := ResolveSecrets(ctx); err != nil {
+		return nil, err
+	}
+	s, err := CurrentSettings()
+	return s, errors.Join(err, s.Validate())
+}
+
+// CurrentSettings parses the current Values, Validate is not called
+func CurrentSettings() (*Settings, error) {
+	var errs []error
+	urlFor := func(key string) *url.URL {
+		u, err := parseURL(GetValueFor(key))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+		return u
+	}
+
+	s := &Settings{
+		FulcioURL:       urlFor(FulcioURL),
+		RekorURL:        urlFor(RekorURL),
+		TufURL:          urlFor(TufURL),
+		OidcIssuerURL:   urlFor(OidcIssuerURL),
+		TsaURL:          urlFor(TsaURL),
+		OidcToken:       GetValueFor(OidcToken),
+		OidcUser:        GetValueFor(OidcUser),
+		OidcPassword:    GetValueFor(OidcPassword),
+		OidcUserDomain:  GetValueFor(OidcUserDomain),
+		OidcRealm:       GetValueFor(OidcRealm),
+		OidcClientID:    GetValueFor(OidcClientID),
+		CosignBinary:    GetValueFor(CosignBinary),
+		TargetImageName: GetValueFor(TargetImageName),
+		Profile:         GetValueFor(ProfileName),
+	}
+	if value := GetValueFor(CLITimeout); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout < 0 {
+			errs = append(errs, fmt.Errorf("%s: invalid duration %q", CLITimeout, value))
+		}
+		s.CLITimeout = timeout
+	}
+	if value := GetValueFor(ManualImageSetup); value != "" {
+		manual, err := strconv.ParseBool(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid boolean %q", ManualImageSetup, value))
+		}
+		s.ManualImageSetup = manual
+	}
+	return s, errors.Join(errs...)
+}
+
+// Validate checks that the required values are set
+func (s *Settings) Validate() error {
+	var errs []error
+	required := []struct {
+		key string
+		url *url.URL
+	}{{FulcioURL, s.FulcioURL}, {RekorURL, s.RekorURL}, {TufURL, s.TufURL}, {OidcIssuerURL, s.OidcIssuerURL}}
+	for _, r := range required {
+		if r.url == nil {
+			errs = append(errs, fmt.Errorf("%s is required", r.key))
+		}
+	}
+	if s.OidcToken == "" && (s.OidcUser == "" || s.OidcPassword == "") {
+		errs = append(errs, fmt.Errorf("%s or %s and %s are required", OidcToken, OidcUser, OidcPassword))
+	}
+	if s.ManualImageSetup && s.TargetImageName == "" {
+		errs = append(errs, fmt.Errorf("%s is required if %s is true", TargetImageName, ManualImageSetup))
+	}
+	return errors.Join(errs...)
+}
+
+// String returns one KEY=value line per value, secrets and resolved secret references are redacted
+func (s *Settings) String() string {
+	values := map[string]string{
+		FulcioURL:        urlString(s.FulcioURL),
+		RekorURL:         urlString(s.RekorURL),
+		TufURL:           urlString(s.TufURL),
+		OidcIssuerURL:    urlString(s.OidcIssuerURL),
+		TsaURL:           urlString(s.TsaURL),
+		OidcToken:        s.OidcToken,
+		OidcUser:         s.OidcUser,
+		OidcPassword:     s.OidcPassword,
+		OidcUserDomain:   s.OidcUserDomain,
+		OidcRealm:        s.OidcRealm,
+		OidcClientID:     s.OidcClientID,
+		CosignBinary:     s.CosignBinary,
+		ManualImageSetup: strconv.FormatBool(s.ManualImageSetup),
+		TargetImageName:  s.TargetImageName,
+		ProfileName:      s.Profile,
+	}
+	if s.CLITimeout != 0 {
+		values[CLITimeout] = s.CLITimeout.String()
+	}
+
+	var b strings.Builder
+	for _, setting := range settings {
+		value := values[setting.key]
+		if setting.secret && value != "" {
+			value = redacted
+		}
+		value = secrets.Mask(value)
+		fmt.Fprintf(&b, "%s=%s\n", setting.key, value)
+	}
+	return b.String()
+}
+
+func settingKeys() []string {
+	keys := make([]string, len(settings))
+	for i, s := range settings {
+		keys[i] = s.key
+	}
+	return keys
+}
+
+// parseURL returns nil for an empty value and requires an absolute http(s) URL otherwise
+func parseURL(value string) (*url.URL, error) {
+	if value == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %q: %w", value, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid URL %q: an absolute http or https URL is required", value)
+	}
+	return u, nil
+}
+
+// urlString returns the URL with a password in it redacted
+func urlString(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	return u.Redacted()
+}
@@ -19,9 +19,18 @@ const (
 	OidcRealm      = "KEYCLOAK_REALM"   // Default: "trusted-artifact-signer"
 	OidcClientID   = "OIDC_CLIENT_ID"   // Default: "trusted-artifact-signer"
 
+	// CLI tools
+	CosignBinary = "COSIGN_BINARY" // Optional, cosign is looked up in PATH if not set
+	CLITimeout   = "CLI_TIMEOUT"   // Optional, timeout of a single CLI command, e.g. "5m"
+
 	// Image Setup
 	ManualImageSetup = "MANUAL_IMAGE_SETUP" // Default: "false"
 	TargetImageName  = "TARGET_IMAGE_NAME"  // Required if ManualImageSetup=true
+
+	// SettingsFile is an optional YAML, JSON or env file with any of the values above
+	SettingsFile = "SETTINGS_FILE"
+	// ProfileName selects a named Profile of the SETTINGS_FILE
+	ProfileName = "RHTAS_PROFILE"
 )
 
 // Values holds the Viper instance for configuration management
@@ -46,3 +55,24 @@ func init() {
 func GetValueFor(key string) string {
 	return Values.GetString(key)
 }
+
+// discovered holds the keys set by SetDiscovered since the last ResetDiscovered
+var discovered = map[string]bool{}
+
+// SetDiscovered sets a value found on the cluster, e.g. a service URL, with the lowest priority
+// Values set in the environment or with Values.Set are never overridden, a later discovery replaces the value
+// It returns false if an explicitly set value is kept
+func SetDiscovered(key, value string) bool {
+	Values.SetDefault(key, value)
+	discovered[key] = true
+	return GetValueFor(key) == value
+}
+
+// ResetDiscovered removes all values set by SetDiscovered, e.g. before discovering the services of another installation
+// Keys without a discovered value are kept, so discovery must not be used for keys with a built-in default
+func ResetDiscovered() {
+	for key := range discovered {
+		Values.SetDefault(key, nil)
+	}
+	discovered = map[string]bool{}
+}
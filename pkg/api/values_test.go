@@ -54,3 +54,40 @@ var _ = Describe("Configuration Values", func() {
 		Expect(GetValueFor(OidcIssuerURL)).To(BeEmpty())
 	})
 })
+
+var _ = Describe("Discovered Values", func() {
+	BeforeEach(func() {
+		os.Clearenv()
+		Values = viper.New()
+		Values.AutomaticEnv()
+	})
+
+	It("should set values that are not set explicitly", func() {
+		Expect(SetDiscovered(FulcioURL, "https://fulcio.cluster.example.com")).To(BeTrue())
+		Expect(GetValueFor(FulcioURL)).To(Equal("https://fulcio.cluster.example.com"))
+
+		Expect(SetDiscovered(FulcioURL, "https://fulcio.other.example.com")).To(BeTrue())
+		Expect(GetValueFor(FulcioURL)).To(Equal("https://fulcio.other.example.com"))
+	})
+
+	It("should keep values from the environment and Set", func() {
+		_ = os.Setenv("SIGSTORE_REKOR_URL", "https://rekor.example.com")
+		Values.Set(TufURL, "https://tuf.example.com")
+
+		Expect(SetDiscovered(RekorURL, "https://rekor.cluster.example.com")).To(BeFalse())
+		Expect(SetDiscovered(TufURL, "https://tuf.cluster.example.com")).To(BeFalse())
+		Expect(GetValueFor(RekorURL)).To(Equal("https://rekor.example.com"))
+		Expect(GetValueFor(TufURL)).To(Equal("https://tuf.example.com"))
+	})
+
+	It("should remove discovered values on reset", func() {
+		Values.Set(TufURL, "https://tuf.example.com")
+		Expect(SetDiscovered(TsaURL, "https://tsa.cluster.example.com")).To(BeTrue())
+		Expect(SetDiscovered(TufURL, "https://tuf.cluster.example.com")).To(BeFalse())
+
+		ResetDiscovered()
+		Expect(GetValueFor(TsaURL)).To(BeEmpty())
+		Expect(GetValueFor(TufURL)).To(Equal("https://tuf.example.com"))
+		Expect(GetValueFor(OidcRealm)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,63 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Condition types set by the operator
+const (
+	// ReadyCondition is True once the component and everything it depends on is available
+	ReadyCondition = "Ready"
+)
+
+// LocalObjectReference references an object in the namespace of the custom resource
+type LocalObjectReference struct {
+	Name string `json:"name"`
+}
+
+// SecretKeySelector references a key of a Secret in the namespace of the custom resource
+type SecretKeySelector struct {
+	LocalObjectReference `json:",inline"`
+	Key                  string `json:"key"`
+}
+
+// ExternalAccess exposes a service outside of the cluster with a Route or Ingress
+type ExternalAccess struct {
+	Enabled bool `json:"enabled"`
+	// Host is generated by the operator if empty
+	Host string `json:"host,omitempty"`
+	// RouteSelectorLabels are set on the Route, e.g. to select an ingress controller shard
+	RouteSelectorLabels map[string]string `json:"routeSelectorLabels,omitempty"`
+}
+
+// MonitoringConfig creates a ServiceMonitor for the component
+type MonitoringConfig struct {
+	Enabled bool `json:"enabled"`
+}
+
+// Pvc configures the persistent volume claim of a component
+type Pvc struct {
+	Size *resource.Quantity `json:"size,omitempty"`
+	// Retain keeps the claim when the custom resource is deleted
+	Retain       *bool                               `json:"retain,omitempty"`
+	Name         string                              `json:"name,omitempty"`
+	StorageClass string                              `json:"storageClass,omitempty"`
+	AccessModes  []corev1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+}
+
+// TrillianService is the Trillian log server a transparency log writes to, the one of the Securesign if empty
+type TrillianService struct {
+	Address string `json:"address,omitempty"`
+	Port    *int32 `json:"port,omitempty"`
+}
+
+// CommonStatus is the status shared by all RHTAS custom resources
+type CommonStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ObservedGeneration is the generation the conditions were computed for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// URL is the address of the service, the external one if ExternalAccess is enabled
+	URL string `json:"url,omitempty"`
+}
@@ -0,0 +1,179 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// Object is an RHTAS custom resource with status conditions
+type Object interface {
+	client.Object
+	GetConditions() []metav1.Condition
+}
+
+// GetConditions returns the status conditions
+func (in *Securesign) GetConditions() []metav1.Condition { return in.Status.Conditions }
+
+// GetConditions returns the status conditions
+func (in *Fulcio) GetConditions() []metav1.Condition { return in.Status.Conditions }
+
+// GetConditions returns the status conditions
+func (in *Rekor) GetConditions() []metav1.Condition { return in.Status.Conditions }
+
+// GetConditions returns the status conditions
+func (in *Trillian) GetConditions() []metav1.Condition { return in.Status.Conditions }
+
+// GetConditions returns the status conditions
+func (in *CTlog) GetConditions() []metav1.Condition { return in.Status.Conditions }
+
+// GetConditions returns the status conditions
+func (in *TimestampAuthority) GetConditions() []metav1.Condition { return in.Status.Conditions }
+
+// GetConditions returns the status conditions
+func (in *Tuf) GetConditions() []metav1.Condition { return in.Status.Conditions }
+
+// IsReady returns true if the Ready condition of obj is True
+func IsReady(obj Object) bool {
+	return meta.IsStatusConditionTrue(obj.GetConditions(), ReadyCondition)
+}
+
+// typesScheme knows the RHTAS types only, it is built on first use because the types register in init
+var typesScheme = sync.OnceValue(func() *runtime.Scheme {
+	s := runtime.NewScheme()
+	utilruntime.Must(AddToScheme(s))
+	return s
+})
+
+// New returns an empty typed object for an RHTAS kind, e.g. *Fulcio for "Fulcio"
+func New(kind string) (Object, error) {
+	obj, err := typesScheme().New(GroupVersion.WithKind(kind))
+	if err != nil {
+		return nil, err
+	}
+	typed, ok := obj.(Object)
+	if !ok {
+		return nil, fmt.Errorf("%s is a list, not an RHTAS resource", kind)
+	}
+	return typed, nil
+}
+
+// FromUnstructured converts an RHTAS resource to its typed object, e.g. *Securesign
+// Values of the wrong type are reported, fields the types do not model are dropped
+func FromUnstructured(u *unstructured.Unstructured) (Object, error) {
+	gvk := u.GroupVersionKind()
+	if gvk.GroupVersion() != GroupVersion {
+		return nil, fmt.Errorf("%s %s is not a %s resource", gvk.Kind, u.GetName(), GroupVersion)
+	}
+	obj, err := New(gvk.Kind)
+	if err != nil {
+		return nil, err
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, obj); err != nil {
+		return nil, fmt.Errorf("invalid %s %s: %w", gvk.Kind, u.GetName(), err)
+	}
+	return obj, nil
+}
+
+// FromUnstructuredLenient converts an RHTAS resource like FromUnstructured, but drops values of the wrong type
+// instead of failing, so a mistake in one field, e.g. in a negative scenario, does not hide the others
+// The dropped fields are returned as paths such as "spec.fulcio.externalAccess.enabled" or "spec.tuf.keys[1]"
+func FromUnstructuredLenient(u *unstructured.Unstructured) (Object, []string, error) {
+	obj, err := FromUnstructured(u)
+	if err == nil {
+		return obj, nil, nil
+	}
+	if _, kindErr := New(u.GetKind()); kindErr != nil || u.GroupVersionKind().GroupVersion() != GroupVersion {
+		return nil, nil, err
+	}
+
+	kept := map[string]interface{}{}
+	fits := func() bool {
+		obj, _ = New(u.GetKind())
+		return runtime.DefaultUnstructuredConverter.FromUnstructured(kept, obj) == nil
+	}
+	var dropped []string
+	keepValid(kept, u.Object, "", fits, &dropped)
+	fits()
+	return obj, dropped, nil
+}
+
+// keepValid copies the fields of src to dst that fit converts with, descending into maps and lists to keep as much
+// as possible; a list item is kept or dropped as a whole
+func keepValid(dst, src map[string]interface{}, prefix string, fits func() bool, dropped *[]string) {
+	keys := make([]string, 0, len(src))
+	for key := range src {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		dst[key] = src[key]
+		if fits() {
+			continue
+		}
+		switch value := src[key].(type) {
+		case map[string]interface{}:
+			nested := map[string]interface{}{}
+			if dst[key] = nested; fits() {
+				keepValid(nested, value, path, fits, dropped)
+				continue
+			}
+		case []interface{}:
+			items := []interface{}{}
+			if dst[key] = items; fits() {
+				for i, item := range value {
+					if dst[key] = append(items, item); fits() {
+						items = dst[key].([]interface{})
+					} else {
+						*dropped = append(*dropped, fmt.Sprintf("%s[%d]", path, i))
+					}
+				}
+				dst[key] = items
+				continue
+			}
+		}
+		delete(dst, key)
+		*dropped = append(*dropped, path)
+	}
+}
+
+// ToUnstructured converts a typed object to unstructured, apiVersion and kind are set even if obj has no TypeMeta
+func ToUnstructured(obj Object) (*unstructured.Unstructured, error) {
+	gvks, _, err := typesScheme().ObjectKinds(obj)
+	if err != nil {
+		return nil, err
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %s %s: %w", gvks[0].Kind, obj.GetName(), err)
+	}
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(gvks[0])
+	return u, nil
+}
+
+// Decode parses a YAML or JSON document, e.g. a rendered scenario template, into its typed object
+func Decode(data []byte) (Object, error) {
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse document: %w", err)
+	}
+	u := &unstructured.Unstructured{}
+	if err := u.UnmarshalJSON(jsonData); err != nil {
+		return nil, fmt.Errorf("failed to parse document: %w", err)
+	}
+	return FromUnstructured(u)
+}
@@ -0,0 +1,179 @@
+package v1alpha1
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+func TestV1alpha1(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "RHTAS v1alpha1 API Suite")
+}
+
+// readTemplate returns the documents of a scenario template with the runtime placeholders replaced
+func readTemplate(scenario, variant, name string) []string {
+	_, file, _, _ := runtime.Caller(0)
+	root := filepath.Join(filepath.Dir(file), "..", "..", "..", "..")
+	data, err := os.ReadFile(filepath.Join(root, "scenarios", scenario, variant, name))
+	Expect(err).NotTo(HaveOccurred())
+	content := strings.NewReplacer("{{NAMESPACE}}", "rhtas-test", "{{INSTANCE_NAME}}", "securesign-sample").Replace(string(data))
+	return strings.Split(content, "\n---\n")
+}
+
+var _ = Describe("Conversion", func() {
+	It("should decode the Securesign template into typed objects and back", func() {
+		docs := readTemplate("rhtas", "default", "rhtas-default-template.yaml")
+		Expect(docs).To(HaveLen(1))
+
+		obj, err := Decode([]byte(docs[0]))
+		Expect(err).NotTo(HaveOccurred())
+		securesign, ok := obj.(*Securesign)
+		Expect(ok).To(BeTrue())
+		Expect(securesign.Name).To(Equal("securesign-sample"))
+		Expect(securesign.Namespace).To(Equal("rhtas-test"))
+
+		spec := securesign.Spec
+		Expect(spec.Fulcio.Config.OIDCIssuers).To(ConsistOf(OIDCIssuer{
+			ClientID:  "trusted-artifact-signer",
+			Issuer:    "https://your-oidc-issuer-url",
+			IssuerURL: "https://your-oidc-issuer-url",
+			Type:      "email",
+		}))
+		Expect(spec.Fulcio.ExternalAccess.Enabled).To(BeTrue())
+		Expect(*spec.Trillian.Database.Create).To(BeTrue())
+		Expect(spec.TimestampAuthority.NTPMonitoring.Enabled).To(BeTrue())
+		chain := spec.TimestampAuthority.Signer.CertificateChain
+		Expect(chain.IntermediateCA).To(HaveLen(1))
+		Expect(chain.IntermediateCA[0].CommonName).To(Equal("tsa.hostname-intermediate"))
+		Expect(chain.LeafCA.OrganizationEmail).To(Equal("jdoe@redhat.com"))
+		Expect(spec.Tuf.Keys).To(HaveLen(4))
+		Expect(spec.Tuf.Pvc.Size.String()).To(Equal("100Mi"))
+		Expect(spec.Tuf.RootKeySecretRef.Name).To(Equal("tuf-root-keys"))
+
+		// All fields of the template are modeled, so the spec survives the round trip
+		data, err := yaml.YAMLToJSON([]byte(docs[0]))
+		Expect(err).NotTo(HaveOccurred())
+		original := &unstructured.Unstructured{}
+		Expect(original.UnmarshalJSON(data)).To(Succeed())
+		converted, err := ToUnstructured(securesign)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(converted.GroupVersionKind()).To(Equal(SecuresignGVK))
+		Expect(converted.Object["spec"]).To(Equal(original.Object["spec"]))
+		Expect(converted.GetLabels()).To(Equal(original.GetLabels()))
+	})
+
+	It("should decode every kind of a multi-document template", func() {
+		var kinds []string
+		for _, doc := range readTemplate("rhtas", "tr", "rhtas-tr-template.yaml") {
+			obj, err := Decode([]byte(doc))
+			Expect(err).NotTo(HaveOccurred())
+			kinds = append(kinds, obj.GetObjectKind().GroupVersionKind().Kind)
+			if trillian, ok := obj.(*Trillian); ok {
+				Expect(*trillian.Spec.Server.Replicas).To(BeEquivalentTo(1))
+				Expect(*trillian.Spec.Signer.Replicas).To(BeEquivalentTo(1))
+			}
+		}
+		Expect(kinds).To(ContainElements("Trillian", "Rekor"))
+	})
+
+	It("should read the status conditions", func() {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "rhtas.redhat.com/v1alpha1",
+			"kind":       "Fulcio",
+			"metadata":   map[string]interface{}{"name": "fulcio", "generation": int64(2)},
+			"status": map[string]interface{}{
+				"url":                "https://fulcio.example.com",
+				"observedGeneration": int64(2),
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Ready", "status": "True", "reason": "Ready", "lastTransitionTime": "2026-10-18T12:00:00Z"},
+				},
+			},
+		}}
+		obj, err := FromUnstructured(u)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(IsReady(obj)).To(BeTrue())
+		fulcio := obj.(*Fulcio)
+		Expect(fulcio.Status.URL).To(Equal("https://fulcio.example.com"))
+		Expect(fulcio.Status.ObservedGeneration).To(Equal(fulcio.Generation))
+
+		Expect(IsReady(&Rekor{})).To(BeFalse())
+	})
+
+	It("should report values of the wrong type and foreign kinds", func() {
+		_, err := Decode([]byte(`apiVersion: rhtas.redhat.com/v1alpha1
+kind: Rekor
+metadata:
+  name: rekor
+spec:
+  externalAccess:
+    enabled: "yes"
+`))
+		Expect(err).To(MatchError(ContainSubstring("invalid Rekor rekor")))
+
+		_, err = Decode([]byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\n"))
+		Expect(err).To(MatchError("ConfigMap config is not a rhtas.redhat.com/v1alpha1 resource"))
+
+		_, err = New("Ctlog")
+		Expect(err).To(HaveOccurred())
+		_, err = New("CTlogList")
+		Expect(err).To(MatchError("CTlogList is a list, not an RHTAS resource"))
+	})
+
+	It("should drop values of the wrong type only with the lenient conversion", func() {
+		u := &unstructured.Unstructured{}
+		Expect(yaml.Unmarshal([]byte(`apiVersion: rhtas.redhat.com/v1alpha1
+kind: Securesign
+metadata:
+  name: sample
+spec:
+  fulcio:
+    externalAccess:
+      enabled: "yes"
+    monitoring:
+      enabled: true
+  tuf:
+    keys:
+      - name: rekor.pub
+      - name: [ctfe.pub]
+      - name: fulcio_v1.crt.pem
+`), &u.Object)).To(Succeed())
+
+		obj, dropped, err := FromUnstructuredLenient(u)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dropped).To(Equal([]string{"spec.fulcio.externalAccess.enabled", "spec.tuf.keys[1]"}))
+		securesign := obj.(*Securesign)
+		Expect(securesign.Name).To(Equal("sample"))
+		Expect(securesign.Spec.Fulcio.Monitoring.Enabled).To(BeTrue())
+		Expect(securesign.Spec.Fulcio.ExternalAccess.Enabled).To(BeFalse())
+		Expect(securesign.Spec.Tuf.Keys).To(Equal([]TufKey{{Name: "rekor.pub"}, {Name: "fulcio_v1.crt.pem"}}))
+
+		_, err = FromUnstructured(u)
+		Expect(err).To(MatchError(ContainSubstring("invalid Securesign sample")))
+		_, _, err = FromUnstructuredLenient(&unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "v1", "kind": "Secret"}})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should set the kind of objects without TypeMeta and copy them deeply", func() {
+		tuf := &Tuf{
+			ObjectMeta: metav1.ObjectMeta{Name: "tuf"},
+			Spec:       TufSpec{Keys: []TufKey{{Name: "rekor.pub", SecretRef: &SecretKeySelector{Key: "public"}}}},
+		}
+		u, err := ToUnstructured(tuf)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(u.GetKind()).To(Equal("Tuf"))
+		Expect(u.GetAPIVersion()).To(Equal("rhtas.redhat.com/v1alpha1"))
+
+		copied := tuf.DeepCopy()
+		copied.Spec.Keys[0].SecretRef.Key = "changed"
+		Expect(tuf.Spec.Keys[0].SecretRef.Key).To(Equal("public"))
+	})
+})
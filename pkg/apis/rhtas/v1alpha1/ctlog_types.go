@@ -0,0 +1,54 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CTlogSpec is the desired state of CTlog
+type CTlogSpec struct {
+	Monitoring *MonitoringConfig `json:"monitoring,omitempty"`
+	Trillian   *TrillianService  `json:"trillian,omitempty"`
+	// TreeID is the Trillian tree of the log, created by the operator if nil
+	TreeID                *int64             `json:"treeID,omitempty"`
+	PrivateKeyRef         *SecretKeySelector `json:"privateKeyRef,omitempty"`
+	PrivateKeyPasswordRef *SecretKeySelector `json:"privateKeyPasswordRef,omitempty"`
+	PublicKeyRef          *SecretKeySelector `json:"publicKeyRef,omitempty"`
+	// RootCertificates are the CAs whose certificates are accepted, the Fulcio CA if empty
+	RootCertificates []SecretKeySelector   `json:"rootCertificates,omitempty"`
+	ServerConfigRef  *LocalObjectReference `json:"serverConfigRef,omitempty"`
+}
+
+// CTlogStatus is the observed state of CTlog
+type CTlogStatus struct {
+	CommonStatus          `json:",inline"`
+	ServerConfigRef       *LocalObjectReference `json:"serverConfigRef,omitempty"`
+	PrivateKeyRef         *SecretKeySelector    `json:"privateKeyRef,omitempty"`
+	PrivateKeyPasswordRef *SecretKeySelector    `json:"privateKeyPasswordRef,omitempty"`
+	PublicKeyRef          *SecretKeySelector    `json:"publicKeyRef,omitempty"`
+	RootCertificates      []SecretKeySelector   `json:"rootCertificates,omitempty"`
+	TreeID                *int64                `json:"treeID,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CTlog is the certificate transparency log of the certificates issued by Fulcio
+type CTlog struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CTlogSpec   `json:"spec,omitempty"`
+	Status CTlogStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CTlogList is a list of CTlog
+type CTlogList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CTlog `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CTlog{}, &CTlogList{})
+}
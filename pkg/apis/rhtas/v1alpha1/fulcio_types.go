@@ -0,0 +1,84 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FulcioSpec is the desired state of Fulcio
+type FulcioSpec struct {
+	ExternalAccess *ExternalAccess       `json:"externalAccess,omitempty"`
+	Monitoring     *MonitoringConfig     `json:"monitoring,omitempty"`
+	Config         *FulcioConfig         `json:"config,omitempty"`
+	Certificate    *FulcioCert           `json:"certificate,omitempty"`
+	Ctlog          *CtlogService         `json:"ctlog,omitempty"`
+	TrustedCA      *LocalObjectReference `json:"trustedCA,omitempty"`
+}
+
+// FulcioConfig is the OIDC configuration of Fulcio
+type FulcioConfig struct {
+	OIDCIssuers []OIDCIssuer `json:"OIDCIssuers,omitempty"`
+	MetaIssuers []OIDCIssuer `json:"MetaIssuers,omitempty"`
+}
+
+// OIDCIssuer is an identity provider whose tokens Fulcio accepts
+type OIDCIssuer struct {
+	// Issuer is the iss claim of the tokens
+	Issuer string `json:"Issuer"`
+	// IssuerURL is the URL the discovery document is fetched from, Issuer if empty
+	IssuerURL string `json:"IssuerURL,omitempty"`
+	ClientID  string `json:"ClientID"`
+	// Type of the identity, e.g. email, spiffe, kubernetes or github-workflow
+	Type              string `json:"Type"`
+	CIProvider        string `json:"CIProvider,omitempty"`
+	IssuerClaim       string `json:"IssuerClaim,omitempty"`
+	SubjectDomain     string `json:"SubjectDomain,omitempty"`
+	SPIFFETrustDomain string `json:"SPIFFETrustDomain,omitempty"`
+}
+
+// FulcioCert is the CA of Fulcio, generated by the operator unless CARef is set
+type FulcioCert struct {
+	PrivateKeyRef         *SecretKeySelector `json:"privateKeyRef,omitempty"`
+	PrivateKeyPasswordRef *SecretKeySelector `json:"privateKeyPasswordRef,omitempty"`
+	CARef                 *SecretKeySelector `json:"caRef,omitempty"`
+	CommonName            string             `json:"commonName,omitempty"`
+	OrganizationName      string             `json:"organizationName,omitempty"`
+	OrganizationEmail     string             `json:"organizationEmail,omitempty"`
+}
+
+// CtlogService is the CTlog Fulcio submits certificates to, the one of the Securesign if empty
+type CtlogService struct {
+	Address string `json:"address,omitempty"`
+	Port    *int32 `json:"port,omitempty"`
+	Prefix  string `json:"prefix,omitempty"`
+}
+
+// FulcioStatus is the observed state of Fulcio
+type FulcioStatus struct {
+	CommonStatus    `json:",inline"`
+	ServerConfigRef *LocalObjectReference `json:"serverConfigRef,omitempty"`
+	Certificate     *FulcioCert           `json:"certificate,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// Fulcio issues code signing certificates for OIDC identities
+type Fulcio struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FulcioSpec   `json:"spec,omitempty"`
+	Status FulcioStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// FulcioList is a list of Fulcio
+type FulcioList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Fulcio `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Fulcio{}, &FulcioList{})
+}
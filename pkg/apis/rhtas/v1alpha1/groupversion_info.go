@@ -0,0 +1,35 @@
+// Package v1alpha1 contains typed Go structs for the rhtas.redhat.com/v1alpha1 custom resources of the RHTAS operator
+//
+// The structs model the fields the suite renders and checks; fields they do not model are dropped when converting
+// from unstructured, so objects are applied to the cluster as unstructured and converted to typed ones for reading,
+// e.g. by the verifier helpers with FromUnstructuredLenient
+// +kubebuilder:object:generate=true
+// +groupName=rhtas.redhat.com
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/scheme"
+)
+
+var (
+	// GroupVersion is the group and version of the RHTAS custom resources
+	GroupVersion = schema.GroupVersion{Group: "rhtas.redhat.com", Version: "v1alpha1"}
+
+	// SchemeBuilder registers the RHTAS types with a scheme
+	SchemeBuilder = &scheme.Builder{GroupVersion: GroupVersion}
+
+	// AddToScheme adds the RHTAS types to a scheme
+	AddToScheme = SchemeBuilder.AddToScheme
+)
+
+// GroupVersionKinds of the RHTAS custom resources
+var (
+	SecuresignGVK         = GroupVersion.WithKind("Securesign")
+	FulcioGVK             = GroupVersion.WithKind("Fulcio")
+	RekorGVK              = GroupVersion.WithKind("Rekor")
+	TrillianGVK           = GroupVersion.WithKind("Trillian")
+	CTlogGVK              = GroupVersion.WithKind("CTlog")
+	TimestampAuthorityGVK = GroupVersion.WithKind("TimestampAuthority")
+	TufGVK                = GroupVersion.WithKind("Tuf")
+)
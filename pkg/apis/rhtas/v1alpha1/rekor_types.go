@@ -0,0 +1,65 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RekorSpec is the desired state of Rekor
+type RekorSpec struct {
+	ExternalAccess *ExternalAccess   `json:"externalAccess,omitempty"`
+	Monitoring     *MonitoringConfig `json:"monitoring,omitempty"`
+	Trillian       *TrillianService  `json:"trillian,omitempty"`
+	Signer         *RekorSigner      `json:"signer,omitempty"`
+	Pvc            *Pvc              `json:"pvc,omitempty"`
+	RekorSearchUI  *RekorSearchUI    `json:"rekorSearchUI,omitempty"`
+	// TreeID is the Trillian tree of the log, created by the operator if nil
+	TreeID *int64 `json:"treeID,omitempty"`
+}
+
+// RekorSigner is the key Rekor signs entries with, generated by the operator unless KeyRef or KMS is set
+type RekorSigner struct {
+	KMS         string             `json:"kms,omitempty"`
+	KeyRef      *SecretKeySelector `json:"keyRef,omitempty"`
+	PasswordRef *SecretKeySelector `json:"passwordRef,omitempty"`
+}
+
+// RekorSearchUI deploys the search UI of Rekor
+type RekorSearchUI struct {
+	Enabled *bool  `json:"enabled,omitempty"`
+	Host    string `json:"host,omitempty"`
+}
+
+// RekorStatus is the observed state of Rekor
+type RekorStatus struct {
+	CommonStatus     `json:",inline"`
+	ServerConfigRef  *LocalObjectReference `json:"serverConfigRef,omitempty"`
+	Signer           *RekorSigner          `json:"signer,omitempty"`
+	PublicKeyRef     *SecretKeySelector    `json:"publicKeyRef,omitempty"`
+	PvcName          string                `json:"pvcName,omitempty"`
+	RekorSearchUIURL string                `json:"rekorSearchUIUrl,omitempty"`
+	TreeID           *int64                `json:"treeID,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// Rekor is the transparency log of signatures
+type Rekor struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RekorSpec   `json:"spec,omitempty"`
+	Status RekorStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RekorList is a list of Rekor
+type RekorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Rekor `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Rekor{}, &RekorList{})
+}
@@ -0,0 +1,54 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SecuresignSpec is the desired state of Securesign, the operator creates one component CR per set field
+type SecuresignSpec struct {
+	Fulcio             *FulcioSpec             `json:"fulcio,omitempty"`
+	Rekor              *RekorSpec              `json:"rekor,omitempty"`
+	Trillian           *TrillianSpec           `json:"trillian,omitempty"`
+	Ctlog              *CTlogSpec              `json:"ctlog,omitempty"`
+	TimestampAuthority *TimestampAuthoritySpec `json:"tsa,omitempty"`
+	Tuf                *TufSpec                `json:"tuf,omitempty"`
+}
+
+// ServiceStatus is the URL of a component in the Securesign status
+type ServiceStatus struct {
+	URL string `json:"url,omitempty"`
+}
+
+// SecuresignStatus is the observed state of Securesign
+type SecuresignStatus struct {
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	Fulcio             *ServiceStatus     `json:"fulcio,omitempty"`
+	Rekor              *ServiceStatus     `json:"rekor,omitempty"`
+	Tuf                *ServiceStatus     `json:"tuf,omitempty"`
+	TimestampAuthority *ServiceStatus     `json:"tsa,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// Securesign installs the complete RHTAS stack
+type Securesign struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SecuresignSpec   `json:"spec,omitempty"`
+	Status SecuresignStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SecuresignList is a list of Securesign
+type SecuresignList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Securesign `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Securesign{}, &SecuresignList{})
+}
@@ -0,0 +1,79 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TimestampAuthoritySpec is the desired state of TimestampAuthority
+type TimestampAuthoritySpec struct {
+	ExternalAccess *ExternalAccess           `json:"externalAccess,omitempty"`
+	Monitoring     *MonitoringConfig         `json:"monitoring,omitempty"`
+	Signer         *TimestampAuthoritySigner `json:"signer,omitempty"`
+	NTPMonitoring  *NTPMonitoring            `json:"ntpMonitoring,omitempty"`
+	TrustedCA      *LocalObjectReference     `json:"trustedCA,omitempty"`
+}
+
+// TimestampAuthoritySigner is the signer of the timestamps, a certificate chain generated by the operator by default
+type TimestampAuthoritySigner struct {
+	CertificateChain *CertificateChain `json:"certificateChain,omitempty"`
+	File             *TsaFileSigner    `json:"file,omitempty"`
+}
+
+// CertificateChain is the chain of the signing certificate, generated from the CAs unless CertificateChainRef is set
+type CertificateChain struct {
+	CertificateChainRef *SecretKeySelector         `json:"certificateChainRef,omitempty"`
+	RootCA              *TsaCertificateAuthority   `json:"rootCA,omitempty"`
+	IntermediateCA      []*TsaCertificateAuthority `json:"intermediateCA,omitempty"`
+	LeafCA              *TsaCertificateAuthority   `json:"leafCA,omitempty"`
+}
+
+// TsaCertificateAuthority is the subject and optionally the key of a CA in the chain
+type TsaCertificateAuthority struct {
+	CommonName        string             `json:"commonName,omitempty"`
+	OrganizationName  string             `json:"organizationName,omitempty"`
+	OrganizationEmail string             `json:"organizationEmail,omitempty"`
+	PrivateKeyRef     *SecretKeySelector `json:"privateKeyRef,omitempty"`
+	PasswordRef       *SecretKeySelector `json:"passwordRef,omitempty"`
+}
+
+// TsaFileSigner signs with a private key from a Secret
+type TsaFileSigner struct {
+	PrivateKeyRef *SecretKeySelector `json:"privateKeyRef,omitempty"`
+	PasswordRef   *SecretKeySelector `json:"passwordRef,omitempty"`
+}
+
+// NTPMonitoring compares the local time with NTP servers and refuses timestamps when it drifts
+type NTPMonitoring struct {
+	Enabled bool `json:"enabled"`
+}
+
+// TimestampAuthorityStatus is the observed state of TimestampAuthority
+type TimestampAuthorityStatus struct {
+	CommonStatus  `json:",inline"`
+	Signer        *TimestampAuthoritySigner `json:"signer,omitempty"`
+	NTPMonitoring *NTPMonitoring            `json:"ntpMonitoring,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TimestampAuthority is the RFC 3161 timestamp authority
+type TimestampAuthority struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TimestampAuthoritySpec   `json:"spec,omitempty"`
+	Status TimestampAuthorityStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TimestampAuthorityList is a list of TimestampAuthority
+type TimestampAuthorityList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TimestampAuthority `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TimestampAuthority{}, &TimestampAuthorityList{})
+}
@@ -0,0 +1,56 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TrillianSpec is the desired state of Trillian
+type TrillianSpec struct {
+	Database   *TrillianDB       `json:"database,omitempty"`
+	Server     *TrillianServer   `json:"server,omitempty"`
+	Signer     *TrillianServer   `json:"signer,omitempty"`
+	Monitoring *MonitoringConfig `json:"monitoring,omitempty"`
+}
+
+// TrillianDB is the database of Trillian, deployed by the operator if Create is true
+type TrillianDB struct {
+	Create *bool `json:"create,omitempty"`
+	// DatabaseSecretRef holds the connection details of an external database
+	DatabaseSecretRef *LocalObjectReference `json:"databaseSecretRef,omitempty"`
+	Pvc               *Pvc                  `json:"pvc,omitempty"`
+}
+
+// TrillianServer configures the log server or the log signer deployment
+type TrillianServer struct {
+	Replicas *int32 `json:"replicas,omitempty"`
+}
+
+// TrillianStatus is the observed state of Trillian
+type TrillianStatus struct {
+	CommonStatus `json:",inline"`
+	Database     *TrillianDB `json:"database,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// Trillian is the Merkle tree storage of Rekor and CTlog
+type Trillian struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TrillianSpec   `json:"spec,omitempty"`
+	Status TrillianStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TrillianList is a list of Trillian
+type TrillianList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Trillian `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Trillian{}, &TrillianList{})
+}
@@ -0,0 +1,53 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TufSpec is the desired state of Tuf
+type TufSpec struct {
+	ExternalAccess *ExternalAccess `json:"externalAccess,omitempty"`
+	Port           int32           `json:"port,omitempty"`
+	// Keys are the targets of the repository, the keys of the Securesign components by default
+	Keys []TufKey `json:"keys,omitempty"`
+	Pvc  *Pvc     `json:"pvc,omitempty"`
+	// RootKeySecretRef holds the keys the repository metadata is signed with
+	RootKeySecretRef *LocalObjectReference `json:"rootKeySecretRef,omitempty"`
+}
+
+// TufKey is a target of the repository, resolved from the component of the same name unless SecretRef is set
+type TufKey struct {
+	Name      string             `json:"name"`
+	SecretRef *SecretKeySelector `json:"secretRef,omitempty"`
+}
+
+// TufStatus is the observed state of Tuf
+type TufStatus struct {
+	CommonStatus `json:",inline"`
+	Keys         []TufKey `json:"keys,omitempty"`
+	PvcName      string   `json:"pvcName,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// Tuf is the TUF repository of the trust root
+type Tuf struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TufSpec   `json:"spec,omitempty"`
+	Status TufStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TufList is a list of Tuf
+type TufList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Tuf `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Tuf{}, &TufList{})
+}
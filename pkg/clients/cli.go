@@ -0,0 +1,157 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/petrpinkas/config-examples/pkg/api"
+)
+
+// DefaultCLITimeout is the timeout of a single CLI command
+const DefaultCLITimeout = 2 * time.Minute
+
+// cliTimeout returns CLI_TIMEOUT, zero if it is not set or invalid (Settings.Validate reports invalid values)
+func cliTimeout() time.Duration {
+	timeout, err := time.ParseDuration(api.GetValueFor(api.CLITimeout))
+	if err != nil || timeout < 0 {
+		return 0
+	}
+	return timeout
+}
+
+// ErrBinaryNotFound means the setup strategy could not locate the binary of a CLI tool
+var ErrBinaryNotFound = errors.New("binary not found")
+
+// SetupStrategy locates the binary of a CLI tool
+type SetupStrategy func(name string) (string, error)
+
+// BinaryPath uses the binary at path
+func BinaryPath(path string) SetupStrategy {
+	return func(name string) (string, error) {
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s: %w", ErrBinaryNotFound, name, err)
+		}
+		if info.IsDir() || info.Mode()&0o111 == 0 {
+			return "", fmt.Errorf("%w: %s: %s is not executable", ErrBinaryNotFound, name, path)
+		}
+		return path, nil
+	}
+}
+
+// LookPath looks the binary up in PATH
+func LookPath() SetupStrategy {
+	return func(name string) (string, error) {
+		path, err := exec.LookPath(name)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s: %w", ErrBinaryNotFound, name, err)
+		}
+		return path, nil
+	}
+}
+
+// PreferredSetupStrategy uses the binary from the <NAME>_BINARY api value (e.g. COSIGN_BINARY) if set
+// and looks it up in PATH otherwise
+func PreferredSetupStrategy() SetupStrategy {
+	return func(name string) (string, error) {
+		if path := api.GetValueFor(strings.ToUpper(name) + "_BINARY"); path != "" {
+			return BinaryPath(path)(name)
+		}
+		return LookPath()(name)
+	}
+}
+
+// CommandResult is the output of a finished command
+type CommandResult struct {
+	Stdout   []byte
+	Stderr   []byte
+	ExitCode int
+}
+
+// cli runs the commands of a CLI tool with a timeout and additional environment variables
+type cli struct {
+	Name string
+	// Env is added to the environment of the current process, entries have the form KEY=value
+	Env []string
+	// Timeout of a single command, DefaultCLITimeout if zero
+	Timeout        time.Duration
+	setupStrategy  SetupStrategy
+	versionCommand string
+	path           string
+}
+
+// Setup locates the binary, it is called by the first command
+func (c *cli) Setup() error {
+	if c.path != "" {
+		return nil
+	}
+	path, err := c.setupStrategy(c.Name)
+	if err != nil {
+		return err
+	}
+	c.path = path
+	return nil
+}
+
+// Path returns the located binary, empty before Setup
+func (c *cli) Path() string {
+	return c.path
+}
+
+// Version returns the output of the version command
+func (c *cli) Version(ctx context.Context) (string, error) {
+	result, err := c.Run(ctx, strings.Fields(c.versionCommand)...)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(result.Stdout) + string(result.Stderr)), nil
+}
+
+// Run executes the binary with args and waits for it to finish
+// A non-zero exit code is returned as error that includes stderr, the result is returned in any case
+func (c *cli) Run(ctx context.Context, args ...string) (*CommandResult, error) {
+	if err := c.Setup(); err != nil {
+		return nil, err
+	}
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultCLITimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, c.path, args...)
+	cmd.Env = append(os.Environ(), c.Env...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Do not wait for children that keep the output open after the process was killed
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+	result := &CommandResult{Stdout: stdout.Bytes(), Stderr: stderr.Bytes(), ExitCode: -1}
+	if cmd.ProcessState != nil {
+		result.ExitCode = cmd.ProcessState.ExitCode()
+	}
+	command := c.Name + " " + firstArg(args)
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return result, fmt.Errorf("%s timed out after %s: %w", command, timeout, ctx.Err())
+	case err != nil:
+		return result, fmt.Errorf("%s failed: %w: %s", command, err, strings.TrimSpace(stderr.String()))
+	}
+	return result, nil
+}
+
+func firstArg(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return args[0]
+}
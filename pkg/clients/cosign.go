@@ -0,0 +1,342 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/petrpinkas/config-examples/pkg/api"
+)
+
+// logIndexPattern matches the log index cosign prints after uploading to Rekor
+var logIndexPattern = regexp.MustCompile(`tlog entry created with index: (\d+)`)
+
+// Cosign drives the cosign CLI; service URLs are passed as flags, cosign uses the public Sigstore instance for empty ones
+type Cosign struct {
+	*cli
+	// Tokens provides the identity token for sign and sign-blob, NewTokenProvider is used if nil
+	Tokens TokenProvider
+	// FulcioURL, OIDCIssuer and OIDCClientID are passed to sign and sign-blob
+	FulcioURL    string
+	OIDCIssuer   string
+	OIDCClientID string
+	// RekorURL is passed to sign, sign-blob, verify and verify-blob
+	RekorURL string
+	// TUFURL is the trust root of verify and verify-blob, cosign is initialized from it before the first verification
+	TUFURL string
+	// TSAURL is passed as --timestamp-server-url to sign and sign-blob if set
+	TSAURL string
+	// TUFRoot is the cosign TUF cache directory (TUF_ROOT)
+	// If empty, the first verification initializes a new temporary directory so ~/.sigstore is never used
+	TUFRoot string
+
+	initialized bool
+}
+
+// CosignBundle is the bundle written by sign-blob --bundle
+type CosignBundle struct {
+	Base64Signature string `json:"base64Signature"`
+	// Cert is the base64 encoded PEM signing certificate
+	Cert        string `json:"cert"`
+	RekorBundle struct {
+		SignedEntryTimestamp []byte `json:"SignedEntryTimestamp"`
+		Payload              struct {
+			Body           string `json:"body"`
+			IntegratedTime int64  `json:"integratedTime"`
+			LogIndex       int64  `json:"logIndex"`
+			LogID          string `json:"logID"`
+		} `json:"Payload"`
+	} `json:"rekorBundle"`
+	RFC3161Timestamp *struct {
+		SignedRFC3161Timestamp []byte `json:"SignedRFC3161Timestamp"`
+	} `json:"rfc3161Timestamp,omitempty"`
+}
+
+// LogEntry returns the Rekor entry of the bundle, e.g. to check it with LogEntry.VerifyHashedRekord
+func (b *CosignBundle) LogEntry() *LogEntry {
+	payload := b.RekorBundle.Payload
+	return &LogEntry{
+		Body:                 payload.Body,
+		IntegratedTime:       payload.IntegratedTime,
+		LogID:                payload.LogID,
+		LogIndex:             payload.LogIndex,
+		SignedEntryTimestamp: b.RekorBundle.SignedEntryTimestamp,
+	}
+}
+
+// SignBlobResult holds the files written by sign-blob
+type SignBlobResult struct {
+	// Signature is the raw signature
+	Signature []byte
+	// Certificate is the PEM signing certificate
+	Certificate []byte
+	BundlePath  string
+	Bundle      *CosignBundle
+}
+
+// SignResult is the outcome of signing an image
+type SignResult struct {
+	// LogIndex is the index of the Rekor entry, -1 if cosign did not report it
+	LogIndex int64
+}
+
+// SignaturePayload is a verified simple signing payload as printed by cosign verify
+type SignaturePayload struct {
+	Critical struct {
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+	// Optional holds cosign annotations such as Issuer, Subject and Bundle
+	Optional map[string]interface{} `json:"optional"`
+}
+
+// NewCosign returns a cosign client configured from the api values: SIGSTORE_FULCIO_URL, SIGSTORE_REKOR_URL,
+// SIGSTORE_OIDC_ISSUER, OIDC_CLIENT_ID, TUF_URL and TSA_URL
+// The SIGSTORE_* values are also set in the environment of cosign, as in the upstream e2e tests
+// The binary is COSIGN_BINARY if set, cosign from PATH otherwise; CLI_TIMEOUT overrides DefaultCLITimeout
+func NewCosign() *Cosign {
+	var env []string
+	for _, key := range []string{api.FulcioURL, api.RekorURL, api.OidcIssuerURL, api.TufURL} {
+		if value := api.GetValueFor(key); value != "" {
+			env = append(env, key+"="+value)
+		}
+	}
+	return &Cosign{
+		cli: &cli{
+			Name:           "cosign",
+			Env:            env,
+			setupStrategy:  PreferredSetupStrategy(),
+			versionCommand: "version",
+			Timeout:        cliTimeout(),
+		},
+		FulcioURL:    api.GetValueFor(api.FulcioURL),
+		RekorURL:     api.GetValueFor(api.RekorURL),
+		OIDCIssuer:   api.GetValueFor(api.OidcIssuerURL),
+		OIDCClientID: api.GetValueFor(api.OidcClientID),
+		TUFURL:       api.GetValueFor(api.TufURL),
+		TSAURL:       api.GetValueFor(api.TsaURL),
+	}
+}
+
+// Run executes cosign, with TUF_ROOT set if configured
+func (c *Cosign) Run(ctx context.Context, args ...string) (*CommandResult, error) {
+	return c.runWithEnv(ctx, nil, args...)
+}
+
+// runWithEnv executes cosign with env added to the environment of this command only
+func (c *Cosign) runWithEnv(ctx context.Context, env []string, args ...string) (*CommandResult, error) {
+	if c.TUFRoot != "" {
+		env = append(env, "TUF_ROOT="+c.TUFRoot)
+	}
+	if len(env) == 0 {
+		return c.cli.Run(ctx, args...)
+	}
+	if err := c.Setup(); err != nil {
+		return nil, err
+	}
+	withEnv := *c.cli
+	withEnv.Env = append(slices.Clip(c.Env), env...)
+	return withEnv.Run(ctx, args...)
+}
+
+// runWithToken executes cosign with the identity token in SIGSTORE_ID_TOKEN, so it is not visible in the process list
+func (c *Cosign) runWithToken(ctx context.Context, token string, args ...string) (*CommandResult, error) {
+	return c.runWithEnv(ctx, []string{"SIGSTORE_ID_TOKEN=" + token}, args...)
+}
+
+// Initialize initializes the cosign trust root in TUFRoot from the TUF repository
+func (c *Cosign) Initialize(ctx context.Context) error {
+	if c.TUFURL == "" {
+		return fmt.Errorf("%w: %s", ErrMissingValue, api.TufURL)
+	}
+	tufURL := strings.TrimSuffix(c.TUFURL, "/")
+	if _, err := c.Run(ctx, "initialize", "--mirror="+tufURL, "--root="+tufURL+"/root.json"); err != nil {
+		return err
+	}
+	c.initialized = true
+	return nil
+}
+
+// ensureInitialized initializes the trust root before the first verification, unless TUFURL is empty
+func (c *Cosign) ensureInitialized(ctx context.Context) error {
+	if c.initialized || c.TUFURL == "" {
+		return nil
+	}
+	if c.TUFRoot == "" {
+		dir, err := os.MkdirTemp("", "cosign-tuf-")
+		if err != nil {
+			return fmt.Errorf("failed to create TUF root: %w", err)
+		}
+		c.TUFRoot = dir
+	}
+	return c.Initialize(ctx)
+}
+
+// SignBlob signs the file at path; the signature, certificate and bundle are written next to it
+// as <path>.sig, <path>.pem and <path>.bundle
+func (c *Cosign) SignBlob(ctx context.Context, path string) (*SignBlobResult, error) {
+	token, err := c.token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := &SignBlobResult{BundlePath: path + ".bundle"}
+	args := append([]string{"sign-blob", "--yes",
+		"--output-signature=" + path + ".sig", "--output-certificate=" + path + ".pem", "--bundle=" + result.BundlePath},
+		c.signArgs()...)
+	if _, err := c.runWithToken(ctx, token, append(args, path)...); err != nil {
+		return nil, err
+	}
+
+	signature, err := os.ReadFile(path + ".sig")
+	if err != nil {
+		return nil, fmt.Errorf("cosign wrote no signature: %w", err)
+	}
+	if result.Signature, err = base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature))); err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+	certificate, err := os.ReadFile(path + ".pem")
+	if err != nil {
+		return nil, fmt.Errorf("cosign wrote no certificate: %w", err)
+	}
+	if result.Certificate, err = decodePEM(certificate); err != nil {
+		return nil, err
+	}
+	bundle, err := os.ReadFile(result.BundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("cosign wrote no bundle: %w", err)
+	}
+	result.Bundle = &CosignBundle{}
+	if err := json.Unmarshal(bundle, result.Bundle); err != nil {
+		return nil, fmt.Errorf("invalid bundle %s: %w", result.BundlePath, err)
+	}
+	return result, nil
+}
+
+// VerifyBlob verifies the file at path with a bundle written by SignBlob
+func (c *Cosign) VerifyBlob(ctx context.Context, path, bundlePath string, identity Identity) error {
+	if err := c.ensureInitialized(ctx); err != nil {
+		return err
+	}
+	args := append([]string{"verify-blob", "--bundle=" + bundlePath}, c.verifyArgs()...)
+	args = append(args, identityArgs(identity)...)
+	_, err := c.Run(ctx, append(args, path)...)
+	return err
+}
+
+// Sign signs an image and uploads the signature to its registry
+func (c *Cosign) Sign(ctx context.Context, image string) (*SignResult, error) {
+	token, err := c.token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	args := append([]string{"sign", "--yes"}, c.signArgs()...)
+	output, err := c.runWithToken(ctx, token, append(args, image)...)
+	if err != nil {
+		return nil, err
+	}
+	result := &SignResult{LogIndex: -1}
+	for _, out := range [][]byte{output.Stderr, output.Stdout} {
+		if match := logIndexPattern.FindSubmatch(out); match != nil {
+			result.LogIndex, _ = strconv.ParseInt(string(match[1]), 10, 64)
+			break
+		}
+	}
+	return result, nil
+}
+
+// Verify verifies the signatures of an image and returns the verified payloads
+func (c *Cosign) Verify(ctx context.Context, image string, identity Identity) ([]SignaturePayload, error) {
+	if err := c.ensureInitialized(ctx); err != nil {
+		return nil, err
+	}
+	args := append([]string{"verify", "--output=json"}, c.verifyArgs()...)
+	args = append(args, identityArgs(identity)...)
+	output, err := c.Run(ctx, append(args, image)...)
+	if err != nil {
+		return nil, err
+	}
+	var payloads []SignaturePayload
+	if err := json.Unmarshal(bytes.TrimSpace(output.Stdout), &payloads); err != nil {
+		return nil, fmt.Errorf("invalid cosign verify output: %w", err)
+	}
+	return payloads, nil
+}
+
+// token returns the identity token, creating the default provider on first use
+func (c *Cosign) token(ctx context.Context) (string, error) {
+	if c.Tokens == nil {
+		tokens, err := NewTokenProvider(nil)
+		if err != nil {
+			return "", err
+		}
+		c.Tokens = tokens
+	}
+	token, err := c.Tokens.Token(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get identity token: %w", err)
+	}
+	return token, nil
+}
+
+// signArgs returns the service flags of sign and sign-blob for the configured URLs
+func (c *Cosign) signArgs() []string {
+	return flagArgs(
+		"--fulcio-url", c.FulcioURL,
+		"--rekor-url", c.RekorURL,
+		"--oidc-issuer", c.OIDCIssuer,
+		"--oidc-client-id", c.OIDCClientID,
+		"--timestamp-server-url", c.TSAURL)
+}
+
+// verifyArgs returns the service flags of verify and verify-blob for the configured URLs
+func (c *Cosign) verifyArgs() []string {
+	return flagArgs("--rekor-url", c.RekorURL)
+}
+
+// flagArgs returns --flag=value for every flag and value pair with a non-empty value
+func flagArgs(pairs ...string) []string {
+	var args []string
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] != "" {
+			args = append(args, pairs[i]+"="+pairs[i+1])
+		}
+	}
+	return args
+}
+
+// identityArgs returns the certificate identity flags, any identity or issuer is accepted for empty fields
+func identityArgs(identity Identity) []string {
+	args := []string{"--certificate-identity-regexp=.*"}
+	if identity.Email != "" {
+		args = []string{"--certificate-identity=" + identity.Email}
+	}
+	if identity.Issuer != "" {
+		return append(args, "--certificate-oidc-issuer="+identity.Issuer)
+	}
+	return append(args, "--certificate-oidc-issuer-regexp=.*")
+}
+
+// decodePEM accepts PEM or base64 encoded PEM, cosign versions differ in what they write
+func decodePEM(data []byte) ([]byte, error) {
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("-----BEGIN")) {
+		return data, nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil || !bytes.HasPrefix(decoded, []byte("-----BEGIN")) {
+		return nil, fmt.Errorf("certificate is neither PEM nor base64 encoded PEM")
+	}
+	return decoded, nil
+}
@@ -0,0 +1,213 @@
+package clients
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/petrpinkas/config-examples/pkg/api"
+)
+
+// fakeCosignScript records its arguments and environment next to itself and answers like cosign
+const fakeCosignScript = `#!/bin/sh
+dir=$(dirname "$0")
+printf '%s\n' "$@" > "$dir/args"
+echo "$1" >> "$dir/commands"
+env | grep -E '^(SIGSTORE_|TUF_)' | sort > "$dir/env"
+case "$1" in
+version) echo "GitVersion:    v2.4.1" ;;
+initialize) ;;
+sign-blob)
+  for arg in "$@"; do
+    case "$arg" in
+    --output-signature=*) printf 'c2lnbmF0dXJl\n' > "${arg#*=}" ;;
+    --output-certificate=*) cp "$dir/cert.b64" "${arg#*=}" ;;
+    --bundle=*) cp "$dir/bundle.json" "${arg#*=}" ;;
+    esac
+  done
+  echo "tlog entry created with index: 42" >&2 ;;
+verify-blob)
+  if [ -f "$dir/fail" ]; then echo "Error: none of the expected identities matched" >&2; exit 1; fi
+  echo "Verified OK" >&2 ;;
+sign) echo "tlog entry created with index: 7" >&2 ;;
+verify)
+  echo "The following checks were performed on each of these signatures:" >&2
+  cat "$dir/verify.json" ;;
+slow) exec sleep 10 ;;
+esac
+`
+
+const fakeBundle = `{
+  "base64Signature": "c2lnbmF0dXJl",
+  "cert": "LS0tLS1CRUdJTg==",
+  "rekorBundle": {
+    "SignedEntryTimestamp": "c2V0",
+    "Payload": {"body": "Ym9keQ==", "integratedTime": 1700000000, "logIndex": 42, "logID": "c0ffee"}
+  }
+}`
+
+const fakeVerifyOutput = `[{"critical":{"identity":{"docker-reference":"ttl.sh/image"},` +
+	`"image":{"docker-manifest-digest":"sha256:abc"},"type":"cosign container image signature"},` +
+	`"optional":{"Issuer":"https://keycloak.example.com/auth/realms/trusted-artifact-signer","Subject":"jdoe@redhat.com"}}]`
+
+// fakeCosign writes the fake executable and its fixtures into a temporary directory
+func fakeCosign() string {
+	dir := GinkgoT().TempDir()
+	binary := filepath.Join(dir, "cosign")
+	Expect(os.WriteFile(binary, []byte(fakeCosignScript), 0o755)).To(Succeed())
+	cert := base64.StdEncoding.EncodeToString(pemCertificate(newCA("signer", nil).cert))
+	Expect(os.WriteFile(filepath.Join(dir, "cert.b64"), []byte(cert), 0o644)).To(Succeed())
+	Expect(os.WriteFile(filepath.Join(dir, "bundle.json"), []byte(fakeBundle), 0o644)).To(Succeed())
+	Expect(os.WriteFile(filepath.Join(dir, "verify.json"), []byte(fakeVerifyOutput), 0o644)).To(Succeed())
+	return binary
+}
+
+// recorded returns the lines the fake wrote to args or env
+func recorded(binary, name string) []string {
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(binary), name))
+	Expect(err).NotTo(HaveOccurred())
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+var _ = Describe("Cosign", func() {
+	var (
+		binary string
+		cosign *Cosign
+	)
+
+	BeforeEach(func() {
+		binary = fakeCosign()
+		useValues(map[string]string{
+			api.CosignBinary:  binary,
+			api.FulcioURL:     "https://fulcio.example.com",
+			api.RekorURL:      "https://rekor.example.com",
+			api.OidcIssuerURL: "https://keycloak.example.com/auth/realms/trusted-artifact-signer",
+			api.OidcClientID:  "trusted-artifact-signer",
+			api.TufURL:        "https://tuf.example.com/",
+			api.TsaURL:        "https://tsa.example.com/api/v1/timestamp",
+			api.OidcToken:     "identity-token",
+		})
+		cosign = NewCosign()
+	})
+
+	It("should locate the binary and report its version", func(ctx SpecContext) {
+		version, err := cosign.Version(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(version).To(ContainSubstring("v2.4.1"))
+		Expect(cosign.Path()).To(Equal(binary))
+	})
+
+	It("should initialize from TUF with the api values as environment", func(ctx SpecContext) {
+		cosign.TUFRoot = filepath.Join(GinkgoT().TempDir(), "tuf")
+		Expect(cosign.Initialize(ctx)).To(Succeed())
+
+		Expect(recorded(binary, "args")).To(Equal([]string{"initialize",
+			"--mirror=https://tuf.example.com", "--root=https://tuf.example.com/root.json"}))
+		Expect(recorded(binary, "env")).To(ContainElements(
+			"SIGSTORE_FULCIO_URL=https://fulcio.example.com",
+			"SIGSTORE_REKOR_URL=https://rekor.example.com",
+			"SIGSTORE_OIDC_ISSUER=https://keycloak.example.com/auth/realms/trusted-artifact-signer",
+			"TUF_URL=https://tuf.example.com/",
+			"TUF_ROOT="+cosign.TUFRoot,
+		))
+	})
+
+	It("should sign a blob and parse the written files", func(ctx SpecContext) {
+		blob := filepath.Join(GinkgoT().TempDir(), "artifact.txt")
+		Expect(os.WriteFile(blob, []byte("content"), 0o644)).To(Succeed())
+
+		result, err := cosign.SignBlob(ctx, blob)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Signature).To(Equal([]byte("signature")))
+		Expect(string(result.Certificate)).To(HavePrefix("-----BEGIN CERTIFICATE-----"))
+		Expect(result.BundlePath).To(Equal(blob + ".bundle"))
+		Expect(result.Bundle.LogEntry().LogIndex).To(Equal(int64(42)))
+		Expect(result.Bundle.LogEntry().SignedEntryTimestamp).To(Equal([]byte("set")))
+
+		Expect(recorded(binary, "args")).To(Equal([]string{"sign-blob", "--yes",
+			"--output-signature=" + blob + ".sig", "--output-certificate=" + blob + ".pem", "--bundle=" + blob + ".bundle",
+			"--fulcio-url=https://fulcio.example.com", "--rekor-url=https://rekor.example.com",
+			"--oidc-issuer=https://keycloak.example.com/auth/realms/trusted-artifact-signer",
+			"--oidc-client-id=trusted-artifact-signer",
+			"--timestamp-server-url=https://tsa.example.com/api/v1/timestamp", blob}))
+		Expect(recorded(binary, "env")).To(ContainElement("SIGSTORE_ID_TOKEN=identity-token"))
+		Expect(cosign.Env).NotTo(ContainElement(HavePrefix("SIGSTORE_ID_TOKEN=")))
+	})
+
+	It("should verify a blob with the expected identity", func(ctx SpecContext) {
+		Expect(cosign.VerifyBlob(ctx, "artifact.txt", "artifact.txt.bundle", Identity{Email: "jdoe@redhat.com"})).To(Succeed())
+		DeferCleanup(os.RemoveAll, cosign.TUFRoot)
+		Expect(recorded(binary, "args")).To(Equal([]string{"verify-blob", "--bundle=artifact.txt.bundle",
+			"--rekor-url=https://rekor.example.com",
+			"--certificate-identity=jdoe@redhat.com", "--certificate-oidc-issuer-regexp=.*", "artifact.txt"}))
+
+		// The trust root of the installed stack is initialized once, into a directory of its own
+		Expect(cosign.TUFRoot).NotTo(BeEmpty())
+		Expect(recorded(binary, "env")).To(ContainElement("TUF_ROOT=" + cosign.TUFRoot))
+		Expect(cosign.VerifyBlob(ctx, "artifact.txt", "artifact.txt.bundle", Identity{})).To(Succeed())
+		Expect(recorded(binary, "commands")).To(Equal([]string{"initialize", "verify-blob", "verify-blob"}))
+	})
+
+	It("should return stderr and the exit code if a command fails", func(ctx SpecContext) {
+		Expect(os.WriteFile(filepath.Join(filepath.Dir(binary), "fail"), nil, 0o644)).To(Succeed())
+
+		err := cosign.VerifyBlob(ctx, "artifact.txt", "artifact.txt.bundle", Identity{})
+		Expect(err).To(MatchError(ContainSubstring("cosign verify-blob failed")))
+		Expect(err).To(MatchError(ContainSubstring("none of the expected identities matched")))
+
+		result, _ := cosign.Run(ctx, "verify-blob")
+		Expect(result.ExitCode).To(Equal(1))
+	})
+
+	It("should sign and verify an image", func(ctx SpecContext) {
+		signed, err := cosign.Sign(ctx, "ttl.sh/image:5m")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(signed.LogIndex).To(Equal(int64(7)))
+		Expect(recorded(binary, "args")).NotTo(ContainElement(HavePrefix("--identity-token")))
+		Expect(recorded(binary, "env")).To(ContainElement("SIGSTORE_ID_TOKEN=identity-token"))
+
+		payloads, err := cosign.Verify(ctx, "ttl.sh/image:5m", Identity{Issuer: "https://keycloak.example.com/auth/realms/trusted-artifact-signer"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(payloads).To(HaveLen(1))
+		Expect(payloads[0].Critical.Image.DockerManifestDigest).To(Equal("sha256:abc"))
+		Expect(payloads[0].Optional).To(HaveKeyWithValue("Subject", "jdoe@redhat.com"))
+		Expect(recorded(binary, "args")).To(ContainElements("--output=json", "--certificate-identity-regexp=.*",
+			"--certificate-oidc-issuer=https://keycloak.example.com/auth/realms/trusted-artifact-signer"))
+	})
+
+	It("should stop a command after the timeout", func(ctx SpecContext) {
+		cosign.Timeout = 200 * time.Millisecond
+		start := time.Now()
+		_, err := cosign.Run(ctx, "slow")
+		Expect(err).To(MatchError(ContainSubstring("cosign slow timed out after 200ms")))
+		Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+	})
+
+	It("should stop a command after CLI_TIMEOUT", func(ctx SpecContext) {
+		useValues(map[string]string{api.CosignBinary: binary, api.CLITimeout: "300ms"})
+		cosign = NewCosign()
+		Expect(cosign.Timeout).To(Equal(300 * time.Millisecond))
+		_, err := cosign.Run(ctx, "slow")
+		Expect(err).To(MatchError(ContainSubstring("cosign slow timed out after 300ms")))
+
+		useValues(map[string]string{api.CosignBinary: binary, api.CLITimeout: "soon"})
+		Expect(NewCosign().Timeout).To(BeZero())
+	})
+
+	It("should report a missing binary", func(ctx SpecContext) {
+		useValues(map[string]string{api.CosignBinary: filepath.Join(GinkgoT().TempDir(), "missing")})
+		_, err := NewCosign().Version(ctx)
+		Expect(errors.Is(err, ErrBinaryNotFound)).To(BeTrue())
+
+		GinkgoT().Setenv("PATH", filepath.Dir(binary))
+		useValues(nil)
+		path, err := PreferredSetupStrategy()("cosign")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(path).To(Equal(binary))
+	})
+})
@@ -0,0 +1,347 @@
+package clients
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/digitorus/timestamp"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/petrpinkas/config-examples/pkg/clients/tuftest"
+)
+
+const (
+	fakeRealm    = "trusted-artifact-signer"
+	fakeClientID = "trusted-artifact-signer"
+	fakeUser     = "jdoe"
+	fakePassword = "secure"
+	fakeEmail    = "jdoe@redhat.com"
+)
+
+// fakeCA is a certificate with its key
+type fakeCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// newCertificate issues a certificate from template, self-signed if parent is nil
+func newCertificate(template *x509.Certificate, parent *fakeCA) *fakeCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	Expect(err).NotTo(HaveOccurred())
+	template.SerialNumber = serial
+	if template.NotBefore.IsZero() {
+		template.NotBefore = time.Now().Add(-time.Hour)
+		template.NotAfter = time.Now().Add(24 * time.Hour)
+	}
+	issuer, signer := template, key
+	if parent != nil {
+		issuer, signer = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, signer)
+	Expect(err).NotTo(HaveOccurred())
+	cert, err := x509.ParseCertificate(der)
+	Expect(err).NotTo(HaveOccurred())
+	return &fakeCA{cert: cert, key: key}
+}
+
+func newCA(name string, parent *fakeCA) *fakeCA {
+	return newCertificate(&x509.Certificate{
+		Subject:               pkix.Name{CommonName: name},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, parent)
+}
+
+// fakeToken returns an unsigned JWT, the fakes do not verify token signatures
+func fakeToken(issuer, email string, expiry time.Time) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss": issuer, "sub": "3c8a1a0e", "email": email, "aud": fakeClientID,
+		"exp": expiry.Unix(),
+	})
+	Expect(err).NotTo(HaveOccurred())
+	return header + "." + base64.RawURLEncoding.EncodeToString(claims) + ".c2lnbmF0dXJl"
+}
+
+// fakeKeycloak serves OIDC discovery and the password grant of one realm
+type fakeKeycloak struct {
+	*httptest.Server
+	// email and expiresIn go into the issued tokens
+	email     string
+	expiresIn time.Duration
+	// tokens counts the issued tokens
+	tokens atomic.Int32
+}
+
+func newFakeKeycloak() *fakeKeycloak {
+	k := &fakeKeycloak{email: fakeEmail, expiresIn: 5 * time.Minute}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/realms/"+fakeRealm+"/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":         k.issuer(),
+			"token_endpoint": k.issuer() + "/protocol/openid-connect/token",
+		})
+	})
+	mux.HandleFunc("/realms/"+fakeRealm+"/protocol/openid-connect/token", k.token)
+	k.Server = httptest.NewServer(mux)
+	return k
+}
+
+// issuer is the realm URL
+func (k *fakeKeycloak) issuer() string {
+	return k.URL + "/realms/" + fakeRealm
+}
+
+func (k *fakeKeycloak) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.FormValue("grant_type") != "password" || r.FormValue("client_id") != fakeClientID {
+		http.Error(w, `{"error":"invalid_request"}`, http.StatusBadRequest)
+		return
+	}
+	if r.FormValue("username") != fakeUser || r.FormValue("password") != fakePassword {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusUnauthorized)
+		return
+	}
+	k.tokens.Add(1)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token": fakeToken(k.issuer(), k.email, time.Now().Add(k.expiresIn)),
+		"token_type":   "Bearer",
+		"expires_in":   int(k.expiresIn.Seconds()),
+	})
+}
+
+// fakeFulcio issues code signing certificates from an intermediate CA
+type fakeFulcio struct {
+	*httptest.Server
+	root, intermediate *fakeCA
+}
+
+func newFakeFulcio() *fakeFulcio {
+	f := &fakeFulcio{root: newCA("fulcio-root", nil)}
+	f.intermediate = newCA("fulcio-intermediate", f.root)
+	f.Server = httptest.NewServer(http.HandlerFunc(f.signingCert))
+	return f
+}
+
+// chainPEM is the CA chain as published in fulcio_v1.crt.pem
+func (f *fakeFulcio) chainPEM() []byte {
+	return append(pemCertificate(f.intermediate.cert), pemCertificate(f.root.cert)...)
+}
+
+func (f *fakeFulcio) signingCert(w http.ResponseWriter, r *http.Request) {
+	var request fulcioRequest
+	if r.URL.Path != "/api/v2/signingCert" || json.NewDecoder(r.Body).Decode(&request) != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	claims, err := parseClaims(request.Credentials.OIDCIdentityToken)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	block, _ := pem.Decode([]byte(request.PublicKeyRequest.PublicKey.Content))
+	if block == nil {
+		http.Error(w, "invalid public key", http.StatusBadRequest)
+		return
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := verifySignature(publicKey, []byte(claims.principal()), request.PublicKeyRequest.ProofOfPossession); err != nil {
+		http.Error(w, "invalid proof of possession", http.StatusBadRequest)
+		return
+	}
+
+	issuer, err := asn1.MarshalWithParams(claims.Issuer, "utf8")
+	Expect(err).NotTo(HaveOccurred())
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber:    serial,
+		NotBefore:       time.Now().Add(-time.Minute),
+		NotAfter:        time.Now().Add(10 * time.Minute),
+		EmailAddresses:  []string{claims.Email},
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		ExtraExtensions: []pkix.Extension{{Id: oidIssuerV2, Value: issuer}},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, f.intermediate.cert, publicKey, f.intermediate.key)
+	Expect(err).NotTo(HaveOccurred())
+
+	var response fulcioResponse
+	response.SignedCertificateEmbeddedSct.Chain.Certificates = []string{
+		string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pemCertificate(f.intermediate.cert)),
+		string(pemCertificate(f.root.cert)),
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// fakeRekor appends entries to an in-memory log and signs their entry timestamps
+type fakeRekor struct {
+	*httptest.Server
+	key     *ecdsa.PrivateKey
+	mu      sync.Mutex
+	entries int64
+}
+
+func newFakeRekor() *fakeRekor {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	r := &fakeRekor{key: key}
+	r.Server = httptest.NewServer(http.HandlerFunc(r.createEntry))
+	return r
+}
+
+func (r *fakeRekor) publicKeyPEM() []byte {
+	der, err := x509.MarshalPKIXPublicKey(&r.key.PublicKey)
+	Expect(err).NotTo(HaveOccurred())
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func (r *fakeRekor) createEntry(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	var entry hashedRekord
+	if req.URL.Path != "/api/v1/log/entries" || err != nil || json.Unmarshal(body, &entry) != nil || entry.Kind != "hashedrekord" {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	r.mu.Lock()
+	index := r.entries
+	r.entries++
+	r.mu.Unlock()
+
+	logID, err := keyID(&r.key.PublicKey)
+	Expect(err).NotTo(HaveOccurred())
+	e := LogEntry{Body: base64.StdEncoding.EncodeToString(body), IntegratedTime: time.Now().Unix(), LogID: logID, LogIndex: index}
+	payload, err := json.Marshal(struct {
+		Body           string `json:"body"`
+		IntegratedTime int64  `json:"integratedTime"`
+		LogID          string `json:"logID"`
+		LogIndex       int64  `json:"logIndex"`
+	}{e.Body, e.IntegratedTime, e.LogID, e.LogIndex})
+	Expect(err).NotTo(HaveOccurred())
+	digest := sha256.Sum256(payload)
+	set, err := ecdsa.SignASN1(rand.Reader, r.key, digest[:])
+	Expect(err).NotTo(HaveOccurred())
+
+	uuid := hex.EncodeToString(digest[:])
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{uuid: map[string]interface{}{
+		"body": e.Body, "integratedTime": e.IntegratedTime, "logID": e.LogID, "logIndex": e.LogIndex,
+		"verification": map[string]interface{}{"signedEntryTimestamp": set},
+	}})
+}
+
+// fakeTSA answers RFC 3161 requests at /api/v1/timestamp
+type fakeTSA struct {
+	*httptest.Server
+	root, leaf *fakeCA
+}
+
+func newFakeTSA() *fakeTSA {
+	t := &fakeTSA{root: newCA("tsa-root", nil)}
+	t.leaf = newCertificate(&x509.Certificate{
+		Subject:     pkix.Name{CommonName: "tsa-leaf"},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageTimeStamping},
+	}, t.root)
+	t.Server = httptest.NewServer(http.HandlerFunc(t.timestamp))
+	return t
+}
+
+// chainPEM is the chain as published in tsa.certchain.pem, leaf first
+func (t *fakeTSA) chainPEM() []byte {
+	return append(pemCertificate(t.leaf.cert), pemCertificate(t.root.cert)...)
+}
+
+func (t *fakeTSA) timestamp(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if r.URL.Path != timestampPath || err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	request, err := timestamp.ParseRequest(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ts := timestamp.Timestamp{
+		HashAlgorithm:     request.HashAlgorithm,
+		HashedMessage:     request.HashedMessage,
+		Time:              time.Now(),
+		Nonce:             request.Nonce,
+		Policy:            asn1.ObjectIdentifier{1, 2, 3, 4, 1},
+		AddTSACertificate: request.Certificates,
+	}
+	response, err := ts.CreateResponseWithOpts(t.leaf.cert, t.leaf.key, crypto.SHA256)
+	Expect(err).NotTo(HaveOccurred())
+	w.Header().Set("Content-Type", "application/timestamp-reply")
+	_, _ = w.Write(response)
+}
+
+// fakeStack runs Keycloak, Fulcio, Rekor, TSA and TUF in process
+type fakeStack struct {
+	keycloak *fakeKeycloak
+	fulcio   *fakeFulcio
+	rekor    *fakeRekor
+	tsa      *fakeTSA
+	tuf      *httptest.Server
+}
+
+func newFakeStack() *fakeStack {
+	s := &fakeStack{keycloak: newFakeKeycloak(), fulcio: newFakeFulcio(), rekor: newFakeRekor(), tsa: newFakeTSA()}
+	s.tuf = tuftest.New(map[string][]byte{
+		FulcioTarget: s.fulcio.chainPEM(),
+		RekorTarget:  s.rekor.publicKeyPEM(),
+		TSATarget:    s.tsa.chainPEM(),
+	}).Serve()
+	for _, server := range []*httptest.Server{s.keycloak.Server, s.fulcio.Server, s.rekor.Server, s.tsa.Server, s.tuf} {
+		DeferCleanup(server.Close)
+	}
+	return s
+}
+
+// issuer is the Keycloak realm URL
+func (s *fakeStack) issuer() string {
+	return s.keycloak.issuer()
+}
+
+// sigstore returns a client for the stack; the TSA is used if withTSA is set
+func (s *fakeStack) sigstore(withTSA bool) *Sigstore {
+	client := &Sigstore{
+		Fulcio: &Fulcio{URL: s.fulcio.URL},
+		Rekor:  &Rekor{URL: s.rekor.URL},
+		TUFURL: s.tuf.URL,
+		Tokens: &PasswordGrant{IssuerURL: s.issuer(), ClientID: fakeClientID, User: fakeUser, Password: fakePassword},
+	}
+	if withTSA {
+		client.TSA = &TSA{URL: strings.TrimSuffix(s.tsa.URL, "/")}
+	}
+	return client
+}
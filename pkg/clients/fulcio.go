@@ -0,0 +1,88 @@
+package clients
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Fulcio requests short-lived signing certificates
+type Fulcio struct {
+	URL        string
+	HTTPClient *http.Client
+}
+
+// SigningCertificate requests a certificate for the public key of signer, bound to the identity of token
+// It returns the PEM chain, leaf first
+func (f *Fulcio) SigningCertificate(ctx context.Context, token string, signer crypto.Signer) ([]byte, error) {
+	claims, err := parseClaims(token)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := x509.MarshalPKIXPublicKey(signer.Public())
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal public key: %w", err)
+	}
+	// Proof of possession: the identity of the token signed with the private key
+	digest := sha256.Sum256([]byte(claims.principal()))
+	proof, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign proof of possession: %w", err)
+	}
+
+	var request fulcioRequest
+	request.Credentials.OIDCIdentityToken = token
+	request.PublicKeyRequest.PublicKey.Algorithm = "ECDSA"
+	request.PublicKeyRequest.PublicKey.Content = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey}))
+	request.PublicKeyRequest.ProofOfPossession = proof
+
+	var response fulcioResponse
+	if err := doJSON(ctx, f.HTTPClient, http.MethodPost, strings.TrimSuffix(f.URL, "/")+"/api/v2/signingCert", request, &response); err != nil {
+		return nil, fmt.Errorf("Fulcio signing certificate request failed: %w", err)
+	}
+	chain := response.SignedCertificateEmbeddedSct.Chain.Certificates
+	if len(chain) == 0 {
+		chain = response.SignedCertificateDetachedSct.Chain.Certificates
+	}
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("Fulcio returned no certificate")
+	}
+	var pemChain strings.Builder
+	for _, cert := range chain {
+		pemChain.WriteString(strings.TrimSpace(cert) + "\n")
+	}
+	return []byte(pemChain.String()), nil
+}
+
+// fulcioRequest is the body of POST /api/v2/signingCert
+type fulcioRequest struct {
+	Credentials struct {
+		OIDCIdentityToken string `json:"oidcIdentityToken"`
+	} `json:"credentials"`
+	PublicKeyRequest struct {
+		PublicKey struct {
+			Algorithm string `json:"algorithm"`
+			Content   string `json:"content"`
+		} `json:"publicKey"`
+		ProofOfPossession []byte `json:"proofOfPossession"`
+	} `json:"publicKeyRequest"`
+}
+
+// fulcioChain is a PEM certificate chain, leaf first
+type fulcioChain struct {
+	Chain struct {
+		Certificates []string `json:"certificates"`
+	} `json:"chain"`
+}
+
+// fulcioResponse is the answer of POST /api/v2/signingCert, only one of the fields is set
+type fulcioResponse struct {
+	SignedCertificateEmbeddedSct fulcioChain `json:"signedCertificateEmbeddedSct"`
+	SignedCertificateDetachedSct fulcioChain `json:"signedCertificateDetachedSct"`
+}
@@ -0,0 +1,194 @@
+package clients
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/petrpinkas/config-examples/pkg/api"
+)
+
+// tokenExpiryLeeway is how long before its expiry a cached token is replaced
+const tokenExpiryLeeway = 30 * time.Second
+
+// TokenProvider returns an OIDC identity token for Fulcio
+type TokenProvider interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// StaticToken is a token set in advance, e.g. from OIDC_TOKEN
+type StaticToken string
+
+// Token returns the token
+func (t StaticToken) Token(context.Context) (string, error) {
+	if t == "" {
+		return "", fmt.Errorf("%w: %s", ErrMissingValue, api.OidcToken)
+	}
+	return string(t), nil
+}
+
+// PasswordGrant retrieves a token with the resource owner password grant, as Keycloak offers it
+// for the trusted-artifact-signer client
+// The token is cached until shortly before it expires
+type PasswordGrant struct {
+	// IssuerURL is the OIDC issuer, e.g. https://keycloak.example.com/auth/realms/trusted-artifact-signer
+	IssuerURL string
+	ClientID  string
+	User      string
+	Password  string
+	// EmailDomain is the domain the email claim of the token has to belong to, not checked if empty
+	EmailDomain string
+	// HTTPClient is used for discovery and the token request, http.DefaultClient if nil
+	HTTPClient *http.Client
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// NewTokenProvider returns a StaticToken if OIDC_TOKEN is set, otherwise a PasswordGrant
+// for OIDC_USER against SIGSTORE_OIDC_ISSUER that expects an email in OIDC_USER_DOMAIN
+// If the issuer is a plain Keycloak URL without realm, KEYCLOAK_REALM is appended
+func NewTokenProvider(httpClient *http.Client) (TokenProvider, error) {
+	if token := api.GetValueFor(api.OidcToken); token != "" {
+		return StaticToken(token), nil
+	}
+	issuer := api.GetValueFor(api.OidcIssuerURL)
+	if issuer == "" {
+		return nil, fmt.Errorf("%w: %s or %s", ErrMissingValue, api.OidcToken, api.OidcIssuerURL)
+	}
+	issuer = strings.TrimSuffix(issuer, "/")
+	if !strings.Contains(issuer, "/realms/") {
+		issuer += "/realms/" + api.GetValueFor(api.OidcRealm)
+	}
+	return &PasswordGrant{
+		IssuerURL:   issuer,
+		ClientID:    api.GetValueFor(api.OidcClientID),
+		User:        api.GetValueFor(api.OidcUser),
+		Password:    api.GetValueFor(api.OidcPassword),
+		EmailDomain: api.GetValueFor(api.OidcUserDomain),
+		HTTPClient:  httpClient,
+	}, nil
+}
+
+// Token returns the cached token or requests a new one and checks its email claim
+func (g *PasswordGrant) Token(ctx context.Context) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.token != "" && time.Now().Add(tokenExpiryLeeway).Before(g.expiry) {
+		return g.token, nil
+	}
+
+	token, expiresIn, err := g.requestToken(ctx)
+	if err != nil {
+		return "", err
+	}
+	claims, err := parseClaims(token)
+	if err != nil {
+		return "", fmt.Errorf("token for %s: %w", g.User, err)
+	}
+	if err := claims.checkEmailDomain(g.EmailDomain); err != nil {
+		return "", fmt.Errorf("token for %s: %w", g.User, err)
+	}
+	g.token, g.expiry = token, time.Now().Add(expiresIn)
+	if claims.Expiry != 0 {
+		g.expiry = time.Unix(claims.Expiry, 0)
+	}
+	return token, nil
+}
+
+// requestToken discovers the token endpoint of the issuer and requests an access token
+func (g *PasswordGrant) requestToken(ctx context.Context) (string, time.Duration, error) {
+	httpClient := g.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	var discovery struct {
+		TokenEndpoint string `json:"token_endpoint"`
+	}
+	if err := doJSON(ctx, httpClient, http.MethodGet, strings.TrimSuffix(g.IssuerURL, "/")+"/.well-known/openid-configuration", nil, &discovery); err != nil {
+		return "", 0, fmt.Errorf("OIDC discovery failed: %w", err)
+	}
+	if discovery.TokenEndpoint == "" {
+		return "", 0, fmt.Errorf("OIDC discovery of %s returned no token_endpoint", g.IssuerURL)
+	}
+
+	form := url.Values{
+		"grant_type": {"password"},
+		"client_id":  {g.ClientID},
+		"username":   {g.User},
+		"password":   {g.Password},
+		"scope":      {"openid"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	var response struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := do(httpClient, req, &response); err != nil {
+		return "", 0, fmt.Errorf("token request for %s failed: %w", g.User, err)
+	}
+	if response.AccessToken == "" {
+		return "", 0, fmt.Errorf("token response for %s has no access_token", g.User)
+	}
+	return response.AccessToken, time.Duration(response.ExpiresIn) * time.Second, nil
+}
+
+// tokenClaims are the claims of an identity token used to request a certificate
+type tokenClaims struct {
+	Issuer  string `json:"iss"`
+	Subject string `json:"sub"`
+	Email   string `json:"email"`
+	// Expiry is the exp claim in seconds since the epoch
+	Expiry int64 `json:"exp"`
+}
+
+// principal returns the identity Fulcio puts into the certificate: the email if present, the subject otherwise
+func (c tokenClaims) principal() string {
+	if c.Email != "" {
+		return c.Email
+	}
+	return c.Subject
+}
+
+// checkEmailDomain fails if domain is set and the email claim is not an address in it
+func (c tokenClaims) checkEmailDomain(domain string) error {
+	if domain == "" {
+		return nil
+	}
+	if c.Email == "" {
+		return fmt.Errorf("no email claim, expected an address in %s", domain)
+	}
+	if !strings.HasSuffix(strings.ToLower(c.Email), "@"+strings.ToLower(strings.TrimPrefix(domain, "@"))) {
+		return fmt.Errorf("email %s is not in domain %s", c.Email, domain)
+	}
+	return nil
+}
+
+// parseClaims decodes the payload of a JWT without verifying it, Fulcio verifies the token
+func parseClaims(token string) (tokenClaims, error) {
+	var claims tokenClaims
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return claims, fmt.Errorf("token is not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return claims, fmt.Errorf("invalid token payload: %w", err)
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return claims, fmt.Errorf("invalid token claims: %w", err)
+	}
+	return claims, nil
+}
@@ -0,0 +1,88 @@
+package clients
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/petrpinkas/config-examples/pkg/api"
+)
+
+var _ = Describe("PasswordGrant", func() {
+	var (
+		keycloak *fakeKeycloak
+		grant    *PasswordGrant
+	)
+
+	BeforeEach(func() {
+		keycloak = newFakeKeycloak()
+		DeferCleanup(keycloak.Close)
+		grant = &PasswordGrant{IssuerURL: keycloak.issuer(), ClientID: fakeClientID, User: fakeUser, Password: fakePassword,
+			EmailDomain: "redhat.com"}
+	})
+
+	It("should request a token and cache it until it expires", func(ctx SpecContext) {
+		token, err := grant.Token(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		claims, err := parseClaims(token)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(claims.Email).To(Equal(fakeEmail))
+		Expect(claims.Issuer).To(Equal(keycloak.issuer()))
+
+		Expect(grant.Token(ctx)).To(Equal(token))
+		Expect(keycloak.tokens.Load()).To(BeEquivalentTo(1))
+	})
+
+	It("should request a new token shortly before the cached one expires", func(ctx SpecContext) {
+		keycloak.expiresIn = tokenExpiryLeeway / 2
+
+		_, err := grant.Token(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = grant.Token(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keycloak.tokens.Load()).To(BeEquivalentTo(2))
+	})
+
+	It("should reject a token with an email outside the user domain", func(ctx SpecContext) {
+		keycloak.email = "jdoe@example.com"
+
+		_, err := grant.Token(ctx)
+		Expect(err).To(MatchError(ContainSubstring("email jdoe@example.com is not in domain redhat.com")))
+
+		keycloak.email = ""
+		_, err = grant.Token(ctx)
+		Expect(err).To(MatchError(ContainSubstring("no email claim")))
+
+		grant.EmailDomain = ""
+		Expect(grant.Token(ctx)).NotTo(BeEmpty())
+	})
+
+	It("should fail for an unknown realm", func(ctx SpecContext) {
+		grant.IssuerURL = keycloak.URL + "/realms/unknown"
+		_, err := grant.Token(ctx)
+		Expect(err).To(MatchError(ContainSubstring("OIDC discovery failed")))
+		Expect(keycloak.tokens.Load()).To(BeZero())
+	})
+
+	It("should be configured from the api values", func(ctx SpecContext) {
+		useValues(map[string]string{
+			api.OidcIssuerURL:  keycloak.URL + "/",
+			api.OidcRealm:      fakeRealm,
+			api.OidcClientID:   fakeClientID,
+			api.OidcUser:       fakeUser,
+			api.OidcPassword:   fakePassword,
+			api.OidcUserDomain: "redhat.com",
+		})
+
+		provider, err := NewTokenProvider(nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(provider.(*PasswordGrant).IssuerURL).To(Equal(keycloak.issuer()))
+		Expect(provider.(*PasswordGrant).EmailDomain).To(Equal("redhat.com"))
+
+		token, err := provider.Token(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		claims, err := parseClaims(token)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(time.Unix(claims.Expiry, 0)).To(BeTemporally("~", time.Now().Add(keycloak.expiresIn), time.Minute))
+	})
+})
@@ -0,0 +1,169 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Rekor records signatures in the transparency log
+type Rekor struct {
+	URL        string
+	HTTPClient *http.Client
+}
+
+// LogEntry is an entry of the transparency log with its signed entry timestamp
+type LogEntry struct {
+	UUID string `json:"uuid"`
+	// Body is the base64 encoded canonical entry
+	Body           string `json:"body"`
+	IntegratedTime int64  `json:"integratedTime"`
+	// LogID is the hex SHA-256 of the public key of the log
+	LogID    string `json:"logID"`
+	LogIndex int64  `json:"logIndex"`
+	// SignedEntryTimestamp is the signature of the log over body, integratedTime, logID and logIndex
+	SignedEntryTimestamp []byte `json:"signedEntryTimestamp"`
+}
+
+// UploadHashedRekord adds a hashedrekord entry for a SHA-256 digest, its signature and the PEM signing certificate
+func (r *Rekor) UploadHashedRekord(ctx context.Context, digest, signature, certificate []byte) (*LogEntry, error) {
+	entry := hashedRekord{APIVersion: "0.0.1", Kind: "hashedrekord"}
+	entry.Spec.Signature.Content = signature
+	entry.Spec.Signature.PublicKey.Content = certificate
+	entry.Spec.Data.Hash.Algorithm = "sha256"
+	entry.Spec.Data.Hash.Value = hex.EncodeToString(digest)
+
+	var response map[string]struct {
+		Body           string `json:"body"`
+		IntegratedTime int64  `json:"integratedTime"`
+		LogID          string `json:"logID"`
+		LogIndex       int64  `json:"logIndex"`
+		Verification   struct {
+			SignedEntryTimestamp []byte `json:"signedEntryTimestamp"`
+		} `json:"verification"`
+	}
+	if err := doJSON(ctx, r.HTTPClient, http.MethodPost, strings.TrimSuffix(r.URL, "/")+"/api/v1/log/entries", entry, &response); err != nil {
+		return nil, fmt.Errorf("Rekor upload failed: %w", err)
+	}
+	for uuid, e := range response {
+		return &LogEntry{
+			UUID:                 uuid,
+			Body:                 e.Body,
+			IntegratedTime:       e.IntegratedTime,
+			LogID:                e.LogID,
+			LogIndex:             e.LogIndex,
+			SignedEntryTimestamp: e.Verification.SignedEntryTimestamp,
+		}, nil
+	}
+	return nil, fmt.Errorf("Rekor returned no entry")
+}
+
+// VerifyHashedRekord checks that the entry records digest, signature and certificate
+// and that its signed entry timestamp was issued by one of the log keys
+func (e *LogEntry) VerifyHashedRekord(digest, signature, certificate []byte, logKeys []crypto.PublicKey) error {
+	body, err := base64.StdEncoding.DecodeString(e.Body)
+	if err != nil {
+		return fmt.Errorf("invalid entry body: %w", err)
+	}
+	var entry hashedRekord
+	if err := json.Unmarshal(body, &entry); err != nil {
+		return fmt.Errorf("invalid entry body: %w", err)
+	}
+	switch {
+	case entry.Kind != "hashedrekord":
+		return fmt.Errorf("entry is a %s, not a hashedrekord", entry.Kind)
+	case entry.Spec.Data.Hash.Value != hex.EncodeToString(digest):
+		return fmt.Errorf("entry records digest %s", entry.Spec.Data.Hash.Value)
+	case !bytes.Equal(entry.Spec.Signature.Content, signature):
+		return fmt.Errorf("entry records a different signature")
+	case !bytes.Equal(entry.Spec.Signature.PublicKey.Content, certificate):
+		return fmt.Errorf("entry records a different certificate")
+	}
+
+	var logKey crypto.PublicKey
+	for _, key := range logKeys {
+		if id, err := keyID(key); err == nil && id == e.LogID {
+			logKey = key
+		}
+	}
+	if logKey == nil {
+		return fmt.Errorf("entry was logged by unknown log %s", e.LogID)
+	}
+	// The signed entry timestamp covers the canonical JSON of these fields, in this order
+	payload, err := json.Marshal(struct {
+		Body           string `json:"body"`
+		IntegratedTime int64  `json:"integratedTime"`
+		LogID          string `json:"logID"`
+		LogIndex       int64  `json:"logIndex"`
+	}{e.Body, e.IntegratedTime, e.LogID, e.LogIndex})
+	if err != nil {
+		return err
+	}
+	if err := verifySignature(logKey, payload, e.SignedEntryTimestamp); err != nil {
+		return fmt.Errorf("invalid signed entry timestamp: %w", err)
+	}
+	return nil
+}
+
+// hashedRekord is the hashedrekord v0.0.1 entry type
+type hashedRekord struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Spec       struct {
+		Signature struct {
+			Content   []byte `json:"content"`
+			PublicKey struct {
+				Content []byte `json:"content"`
+			} `json:"publicKey"`
+		} `json:"signature"`
+		Data struct {
+			Hash struct {
+				Algorithm string `json:"algorithm"`
+				Value     string `json:"value"`
+			} `json:"hash"`
+		} `json:"data"`
+	} `json:"spec"`
+}
+
+// keyID returns the hex SHA-256 of the DER public key, the log ID of a Rekor instance
+func keyID(key crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// verifySignature verifies a signature over the SHA-256 of message (the message itself for ed25519)
+func verifySignature(key crypto.PublicKey, message, signature []byte) error {
+	digest := sha256.Sum256(message)
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(k, digest[:], signature) {
+			return fmt.Errorf("ECDSA signature does not verify")
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], signature); err != nil {
+			return err
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, message, signature) {
+			return fmt.Errorf("ed25519 signature does not verify")
+		}
+	default:
+		return fmt.Errorf("unsupported key type %T", key)
+	}
+	return nil
+}
@@ -0,0 +1,305 @@
+// Package clients signs and verifies artifacts against an installed RHTAS stack,
+// natively in Go or by driving the cosign CLI
+package clients
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/petrpinkas/config-examples/pkg/api"
+)
+
+// DefaultHTTPTimeout is the timeout of the HTTP client created by NewSigstore
+const DefaultHTTPTimeout = 30 * time.Second
+
+// maxResponseSize limits the responses read from the services
+const maxResponseSize = 10 << 20
+
+// ErrMissingValue means a required api value such as SIGSTORE_FULCIO_URL is not set
+var ErrMissingValue = errors.New("missing value")
+
+var (
+	// oidIssuerV1 is the Fulcio OIDC issuer extension with the raw issuer as value
+	oidIssuerV1 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+	// oidIssuerV2 is the Fulcio OIDC issuer extension with a DER UTF8String as value
+	oidIssuerV2 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+)
+
+// Sigstore signs blobs with a Fulcio certificate and logs them in Rekor, optionally timestamped by the TSA,
+// and verifies the resulting bundles against the trust root published by TUF
+type Sigstore struct {
+	Fulcio *Fulcio
+	Rekor  *Rekor
+	// TSA is nil if no timestamp is requested
+	TSA    *TSA
+	TUFURL string
+	Tokens TokenProvider
+	// HTTPClient is used to fetch the trust root
+	HTTPClient *http.Client
+}
+
+// Bundle is everything needed to verify a signed blob offline
+type Bundle struct {
+	// Certificates is the PEM signing certificate followed by its chain
+	Certificates []byte `json:"certificates"`
+	// Digest is the SHA-256 of the blob
+	Digest    []byte    `json:"digest"`
+	Signature []byte    `json:"signature"`
+	LogEntry  *LogEntry `json:"logEntry"`
+	// Timestamp is the DER RFC 3161 response over the signature, nil if not timestamped
+	Timestamp []byte `json:"timestamp,omitempty"`
+}
+
+// Identity is the expected signer, empty fields are not checked
+type Identity struct {
+	Email  string
+	Issuer string
+}
+
+// Verification describes a verified bundle
+type Verification struct {
+	Email  string
+	Issuer string
+	// SignedAt is the TSA time if the bundle is timestamped, the Rekor integration time otherwise
+	SignedAt    time.Time
+	Timestamped bool
+	LogIndex    int64
+}
+
+// NewSigstore creates a client from the api values: SIGSTORE_FULCIO_URL, SIGSTORE_REKOR_URL and TUF_URL
+// are required, TSA_URL is optional. The identity token comes from NewTokenProvider
+func NewSigstore() (*Sigstore, error) {
+	var missing []string
+	for _, key := range []string{api.FulcioURL, api.RekorURL, api.TufURL} {
+		if api.GetValueFor(key) == "" {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %v", ErrMissingValue, missing)
+	}
+
+	httpClient := &http.Client{Timeout: DefaultHTTPTimeout}
+	tokens, err := NewTokenProvider(httpClient)
+	if err != nil {
+		return nil, err
+	}
+	s := &Sigstore{
+		Fulcio:     &Fulcio{URL: api.GetValueFor(api.FulcioURL), HTTPClient: httpClient},
+		Rekor:      &Rekor{URL: api.GetValueFor(api.RekorURL), HTTPClient: httpClient},
+		TUFURL:     api.GetValueFor(api.TufURL),
+		Tokens:     tokens,
+		HTTPClient: httpClient,
+	}
+	if tsaURL := api.GetValueFor(api.TsaURL); tsaURL != "" {
+		s.TSA = &TSA{URL: tsaURL, HTTPClient: httpClient}
+	}
+	return s, nil
+}
+
+// SignBlob signs blob with an ephemeral key certified by Fulcio, timestamps the signature if a TSA
+// is configured and uploads a hashedrekord entry to Rekor
+func (s *Sigstore) SignBlob(ctx context.Context, blob []byte) (*Bundle, error) {
+	token, err := s.Tokens.Token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get identity token: %w", err)
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	bundle := &Bundle{}
+	if bundle.Certificates, err = s.Fulcio.SigningCertificate(ctx, token, key); err != nil {
+		return nil, err
+	}
+
+	digest := sha256.Sum256(blob)
+	bundle.Digest = digest[:]
+	if bundle.Signature, err = ecdsa.SignASN1(rand.Reader, key, bundle.Digest); err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+	if s.TSA != nil {
+		if bundle.Timestamp, err = s.TSA.Timestamp(ctx, bundle.Signature); err != nil {
+			return nil, err
+		}
+	}
+	leaf, err := leafPEM(bundle.Certificates)
+	if err != nil {
+		return nil, err
+	}
+	if bundle.LogEntry, err = s.Rekor.UploadHashedRekord(ctx, bundle.Digest, bundle.Signature, leaf); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// TrustRoot fetches the trust root from TUF
+func (s *Sigstore) TrustRoot(ctx context.Context) (*TrustRoot, error) {
+	if s.TUFURL == "" {
+		return nil, fmt.Errorf("%w: %s", ErrMissingValue, api.TufURL)
+	}
+	return FetchTrustRoot(ctx, s.HTTPClient, s.TUFURL)
+}
+
+// VerifyBlob fetches the trust root from TUF and verifies bundle for blob, see VerifyBundle
+func (s *Sigstore) VerifyBlob(ctx context.Context, blob []byte, bundle *Bundle, identity Identity) (*Verification, error) {
+	trust, err := s.TrustRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return VerifyBundle(blob, bundle, trust, identity)
+}
+
+// VerifyBundle verifies bundle for blob against trust:
+// the signature matches the signing certificate, the certificate was issued by Fulcio to identity and was valid
+// at the time of signing, the Rekor entry records the signature and carries a valid signed entry timestamp,
+// and the TSA timestamp (if any) is over the signature and chains up to the TSA certificates
+func VerifyBundle(blob []byte, bundle *Bundle, trust *TrustRoot, identity Identity) (*Verification, error) {
+	if bundle.LogEntry == nil {
+		return nil, fmt.Errorf("bundle has no Rekor entry")
+	}
+	digest := sha256.Sum256(blob)
+	if !bytes.Equal(bundle.Digest, digest[:]) {
+		return nil, fmt.Errorf("bundle is for a different blob")
+	}
+	certs, err := ParseCertificates(bundle.Certificates)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bundle certificates: %w", err)
+	}
+	leaf := certs[0]
+	publicKey, ok := leaf.PublicKey.(*ecdsa.PublicKey)
+	if !ok || !ecdsa.VerifyASN1(publicKey, digest[:], bundle.Signature) {
+		return nil, fmt.Errorf("signature does not match the signing certificate")
+	}
+
+	leafPEMBytes, err := leafPEM(bundle.Certificates)
+	if err != nil {
+		return nil, err
+	}
+	if err := bundle.LogEntry.VerifyHashedRekord(bundle.Digest, bundle.Signature, leafPEMBytes, trust.RekorKeys); err != nil {
+		return nil, fmt.Errorf("Rekor entry: %w", err)
+	}
+
+	verification := &Verification{
+		SignedAt: time.Unix(bundle.LogEntry.IntegratedTime, 0),
+		LogIndex: bundle.LogEntry.LogIndex,
+	}
+	if bundle.Timestamp != nil {
+		if verification.SignedAt, err = VerifyTimestamp(bundle.Timestamp, bundle.Signature, trust.TSACertificates); err != nil {
+			return nil, fmt.Errorf("TSA timestamp: %w", err)
+		}
+		verification.Timestamped = true
+	}
+	if err := trust.verifyCertificate(leaf, verification.SignedAt); err != nil {
+		return nil, err
+	}
+
+	verification.Issuer = certificateIssuer(leaf)
+	if len(leaf.EmailAddresses) > 0 {
+		verification.Email = leaf.EmailAddresses[0]
+	}
+	if identity.Email != "" && !slices.Contains(leaf.EmailAddresses, identity.Email) {
+		return nil, fmt.Errorf("certificate identity %v does not match %s", leaf.EmailAddresses, identity.Email)
+	}
+	if identity.Issuer != "" && verification.Issuer != identity.Issuer {
+		return nil, fmt.Errorf("certificate issuer %q does not match %s", verification.Issuer, identity.Issuer)
+	}
+	return verification, nil
+}
+
+// certificateIssuer returns the OIDC issuer recorded by Fulcio in a signing certificate
+func certificateIssuer(cert *x509.Certificate) string {
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(oidIssuerV2) {
+			var issuer string
+			if _, err := asn1.UnmarshalWithParams(ext.Value, &issuer, "utf8"); err == nil {
+				return issuer
+			}
+		}
+	}
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(oidIssuerV1) {
+			return string(ext.Value)
+		}
+	}
+	return ""
+}
+
+// leafPEM returns the first PEM block of a chain
+func leafPEM(chain []byte) ([]byte, error) {
+	certs, err := ParseCertificates(chain)
+	if err != nil {
+		return nil, err
+	}
+	return pemCertificate(certs[0]), nil
+}
+
+// doJSON sends body (if not nil) as JSON and decodes the JSON response into out
+func doJSON(ctx context.Context, httpClient *http.Client, method, url string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	return do(httpClientOrDefault(httpClient), req, out)
+}
+
+// do sends req and decodes the JSON response into out, any non-2xx status is an error
+func do(httpClient *http.Client, req *http.Request, out interface{}) error {
+	resp, err := httpClientOrDefault(httpClient).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return statusError(req.URL.String(), resp.StatusCode, data)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("invalid response from %s: %w", req.URL, err)
+	}
+	return nil
+}
+
+// statusError describes an unexpected HTTP status including the start of the response body
+func statusError(url string, status int, body []byte) error {
+	const maxBody = 200
+	if len(body) > maxBody {
+		body = append(body[:maxBody:maxBody], "..."...)
+	}
+	return fmt.Errorf("%s returned %d %s: %s", url, status, http.StatusText(status), bytes.TrimSpace(body))
+}
+
+func httpClientOrDefault(httpClient *http.Client) *http.Client {
+	if httpClient == nil {
+		return http.DefaultClient
+	}
+	return httpClient
+}
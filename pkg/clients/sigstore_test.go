@@ -0,0 +1,160 @@
+package clients
+
+import (
+	"crypto/x509"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/petrpinkas/config-examples/pkg/api"
+	"github.com/spf13/viper"
+)
+
+func TestClients(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Clients Package Suite")
+}
+
+// useValues replaces api.Values for the current spec
+func useValues(values map[string]string) {
+	previous := api.Values
+	api.Values = viper.New()
+	for key, value := range values {
+		api.Values.Set(key, value)
+	}
+	DeferCleanup(func() { api.Values = previous })
+}
+
+var _ = Describe("Sigstore", func() {
+	var (
+		stack *fakeStack
+		blob  []byte
+	)
+
+	BeforeEach(func() {
+		stack = newFakeStack()
+		blob = []byte("artifact content")
+	})
+
+	It("should sign a blob with a timestamp and verify the bundle against the TUF root", func(ctx SpecContext) {
+		client := stack.sigstore(true)
+
+		bundle, err := client.SignBlob(ctx, blob)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bundle.Timestamp).NotTo(BeEmpty())
+		Expect(bundle.LogEntry.UUID).NotTo(BeEmpty())
+
+		verification, err := client.VerifyBlob(ctx, blob, bundle, Identity{Email: fakeEmail, Issuer: stack.issuer()})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(verification.Email).To(Equal(fakeEmail))
+		Expect(verification.Issuer).To(Equal(stack.issuer()))
+		Expect(verification.Timestamped).To(BeTrue())
+		Expect(verification.LogIndex).To(BeZero())
+	})
+
+	It("should sign without TSA and use the Rekor integration time", func(ctx SpecContext) {
+		client := stack.sigstore(false)
+
+		bundle, err := client.SignBlob(ctx, blob)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bundle.Timestamp).To(BeNil())
+
+		verification, err := client.VerifyBlob(ctx, blob, bundle, Identity{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(verification.Timestamped).To(BeFalse())
+		Expect(verification.SignedAt.Unix()).To(Equal(bundle.LogEntry.IntegratedTime))
+	})
+
+	Context("with a signed bundle", func() {
+		var (
+			client *Sigstore
+			bundle *Bundle
+			trust  *TrustRoot
+		)
+
+		BeforeEach(func(ctx SpecContext) {
+			client = stack.sigstore(true)
+			var err error
+			bundle, err = client.SignBlob(ctx, blob)
+			Expect(err).NotTo(HaveOccurred())
+			trust, err = client.TrustRoot(ctx)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject a different blob", func() {
+			_, err := VerifyBundle([]byte("other content"), bundle, trust, Identity{})
+			Expect(err).To(MatchError(ContainSubstring("different blob")))
+		})
+
+		It("should reject an unexpected identity", func() {
+			_, err := VerifyBundle(blob, bundle, trust, Identity{Email: "someone@example.com"})
+			Expect(err).To(MatchError(ContainSubstring("does not match someone@example.com")))
+
+			_, err = VerifyBundle(blob, bundle, trust, Identity{Issuer: "https://other.example.com"})
+			Expect(err).To(MatchError(ContainSubstring("certificate issuer")))
+		})
+
+		It("should reject a tampered signed entry timestamp", func() {
+			bundle.LogEntry.IntegratedTime++
+			_, err := VerifyBundle(blob, bundle, trust, Identity{})
+			Expect(err).To(MatchError(ContainSubstring("invalid signed entry timestamp")))
+		})
+
+		It("should reject a certificate not issued by the trusted Fulcio", func() {
+			trust.FulcioCertificates = []*x509.Certificate{newCA("other-root", nil).cert}
+			_, err := VerifyBundle(blob, bundle, trust, Identity{})
+			Expect(err).To(MatchError(ContainSubstring("certificate is not trusted")))
+		})
+
+		It("should reject a timestamp of another TSA", func() {
+			trust.TSACertificates = []*x509.Certificate{newCA("other-tsa", nil).cert}
+			_, err := VerifyBundle(blob, bundle, trust, Identity{})
+			Expect(err).To(MatchError(ContainSubstring("not signed by the trusted TSA")))
+		})
+	})
+
+	It("should fail to sign with a wrong password", func(ctx SpecContext) {
+		client := stack.sigstore(false)
+		client.Tokens.(*PasswordGrant).Password = "wrong"
+
+		_, err := client.SignBlob(ctx, blob)
+		Expect(err).To(MatchError(ContainSubstring("401 Unauthorized")))
+	})
+
+	It("should be configured from the api values", func(ctx SpecContext) {
+		useValues(map[string]string{
+			api.FulcioURL:     stack.fulcio.URL,
+			api.RekorURL:      stack.rekor.URL,
+			api.TufURL:        stack.tuf.URL,
+			api.TsaURL:        stack.tsa.URL,
+			api.OidcIssuerURL: stack.keycloak.URL,
+			api.OidcRealm:     fakeRealm,
+			api.OidcClientID:  fakeClientID,
+			api.OidcUser:      fakeUser,
+			api.OidcPassword:  fakePassword,
+		})
+
+		client, err := NewSigstore()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.Tokens.(*PasswordGrant).IssuerURL).To(Equal(stack.issuer()))
+		Expect(client.TSA).NotTo(BeNil())
+
+		bundle, err := client.SignBlob(ctx, blob)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.VerifyBlob(ctx, blob, bundle, Identity{Email: fakeEmail})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should use OIDC_TOKEN and report missing values", func() {
+		useValues(map[string]string{api.FulcioURL: stack.fulcio.URL, api.OidcToken: "token"})
+
+		_, err := NewSigstore()
+		Expect(errors.Is(err, ErrMissingValue)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring(api.RekorURL)))
+
+		provider, err := NewTokenProvider(nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(provider).To(Equal(StaticToken("token")))
+	})
+})
@@ -0,0 +1,143 @@
+package clients
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// TUF targets published by the RHTAS TUF server
+const (
+	FulcioTarget = "fulcio_v1.crt.pem"
+	RekorTarget  = "rekor.pub"
+	CTLogTarget  = "ctfe.pub"
+	TSATarget    = "tsa.certchain.pem"
+)
+
+// TrustRoot holds the keys and certificates clients verify against
+type TrustRoot struct {
+	// FulcioCertificates is the Fulcio CA chain
+	FulcioCertificates []*x509.Certificate
+	RekorKeys          []crypto.PublicKey
+	// TSACertificates is the TSA chain, leaf first, empty if the repository publishes no TSA chain
+	TSACertificates []*x509.Certificate
+}
+
+// FetchTrustRoot downloads the trust root from the TUF repository at tufURL
+// root.json is trusted on first use unless pinned with opts; all metadata and the targets are verified with the
+// TUF client workflow
+func FetchTrustRoot(ctx context.Context, httpClient *http.Client, tufURL string, opts ...TUFOption) (*TrustRoot, error) {
+	up, err := NewTUFUpdater(ctx, httpClient, tufURL, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	download := func(name string) ([]byte, error) {
+		target, err := up.GetTargetInfo(name)
+		if err != nil {
+			return nil, fmt.Errorf("TUF target %s: %w", name, err)
+		}
+		_, data, err := up.DownloadTarget(target, name, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to download TUF target %s: %w", name, err)
+		}
+		return data, nil
+	}
+
+	trust := &TrustRoot{}
+	data, err := download(FulcioTarget)
+	if err != nil {
+		return nil, err
+	}
+	if trust.FulcioCertificates, err = ParseCertificates(data); err != nil {
+		return nil, fmt.Errorf("%s: %w", FulcioTarget, err)
+	}
+	if data, err = download(RekorTarget); err != nil {
+		return nil, err
+	}
+	key, err := parsePublicKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", RekorTarget, err)
+	}
+	trust.RekorKeys = append(trust.RekorKeys, key)
+	if _, ok := up.GetTopLevelTargets()[TSATarget]; ok {
+		if data, err = download(TSATarget); err != nil {
+			return nil, err
+		}
+		if trust.TSACertificates, err = ParseCertificates(data); err != nil {
+			return nil, fmt.Errorf("%s: %w", TSATarget, err)
+		}
+	}
+	return trust, nil
+}
+
+// verifyCertificate checks that cert was issued by the Fulcio CA and was valid at the time of signing
+func (t *TrustRoot) verifyCertificate(cert *x509.Certificate, at time.Time) error {
+	opts := x509.VerifyOptions{
+		Roots:         x509.NewCertPool(),
+		Intermediates: x509.NewCertPool(),
+		CurrentTime:   at,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}
+	for _, ca := range t.FulcioCertificates {
+		if isSelfSigned(ca) {
+			opts.Roots.AddCert(ca)
+		} else {
+			opts.Intermediates.AddCert(ca)
+		}
+	}
+	if _, err := cert.Verify(opts); err != nil {
+		return fmt.Errorf("certificate is not trusted: %w", err)
+	}
+	return nil
+}
+
+// ParseCertificates parses all certificates of a PEM bundle in order
+func ParseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no PEM certificate found")
+	}
+	return certs, nil
+}
+
+// parsePublicKey parses a PEM public key
+func parsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM public key found")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+	return key, nil
+}
+
+func isSelfSigned(cert *x509.Certificate) bool {
+	return cert.CheckSignatureFrom(cert) == nil
+}
+
+// pemCertificate encodes a certificate as PEM
+func pemCertificate(cert *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+}
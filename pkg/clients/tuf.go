@@ -0,0 +1,196 @@
+package clients
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/theupdateframework/go-tuf/v2/metadata"
+	"github.com/theupdateframework/go-tuf/v2/metadata/config"
+	"github.com/theupdateframework/go-tuf/v2/metadata/updater"
+)
+
+// rootMaxLength limits the size of the initial root.json
+const rootMaxLength = 512000
+
+// TUFOptions pins the initial root.json of a TUF repository, which is otherwise trusted on first use
+type TUFOptions struct {
+	// Root is a trusted root.json used instead of the one served by the repository
+	Root []byte
+	// RootKeys must provide the threshold of root signatures of the served root.json
+	RootKeys []crypto.PublicKey
+}
+
+// TUFOption configures NewTUFUpdater
+type TUFOption func(*TUFOptions)
+
+// WithTrustedRoot starts the TUF client workflow from root, e.g. a root.json distributed out of band
+func WithTrustedRoot(root []byte) TUFOption {
+	return func(o *TUFOptions) {
+		o.Root = root
+	}
+}
+
+// WithRootKeys accepts the served root.json only if enough of its root role signatures, as many as the root
+// threshold, are made with keys, e.g. the keys of the rootKeySecretRef Secret of a Tuf
+func WithRootKeys(keys ...crypto.PublicKey) TUFOption {
+	return func(o *TUFOptions) {
+		o.RootKeys = append(o.RootKeys, keys...)
+	}
+}
+
+// NewTUFUpdater runs the TUF client workflow against the repository at tufURL: newer root versions,
+// timestamp.json, snapshot.json and targets.json are downloaded and their signatures, versions and expiry validated
+// The initial root.json is the one of WithTrustedRoot, or it is fetched from tufURL and checked against the keys
+// of WithRootKeys; without either option it is trusted on first use
+// The returned updater downloads targets with httpClient (http.DefaultClient if nil) bound to ctx
+func NewTUFUpdater(ctx context.Context, httpClient *http.Client, tufURL string, opts ...TUFOption) (*updater.Updater, error) {
+	options := &TUFOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	fetcher := &tufFetcher{ctx: ctx, client: httpClientOrDefault(httpClient)}
+	tufURL = strings.TrimSuffix(tufURL, "/")
+
+	root := options.Root
+	if root == nil {
+		var err error
+		if root, err = fetcher.DownloadFile(tufURL+"/root.json", rootMaxLength, 0); err != nil {
+			return nil, fmt.Errorf("failed to fetch root.json: %w", err)
+		}
+		if len(options.RootKeys) > 0 {
+			if err := verifyRootKeys(root, options.RootKeys); err != nil {
+				return nil, err
+			}
+		}
+	}
+	cfg, err := config.New(tufURL, root)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TUF URL %s: %w", tufURL, err)
+	}
+	cfg.Fetcher = fetcher
+	cfg.DisableLocalCache = true
+	up, err := updater.New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid root.json: %w", err)
+	}
+	if err := up.Refresh(); err != nil {
+		return nil, fmt.Errorf("TUF repository %s failed validation: %w", tufURL, err)
+	}
+	return up, nil
+}
+
+// verifyRootKeys checks that root.json is signed with the threshold of its root role, counting only signatures
+// of the root keys that are among pinned
+func verifyRootKeys(data []byte, pinned []crypto.PublicKey) error {
+	root, err := metadata.Root().FromBytes(data)
+	if err != nil {
+		return fmt.Errorf("invalid root.json: %w", err)
+	}
+	// A second copy lists only the pinned keys in its root role, the signed part of root stays untouched
+	delegator, err := metadata.Root().FromBytes(data)
+	if err != nil {
+		return fmt.Errorf("invalid root.json: %w", err)
+	}
+	role, ok := delegator.Signed.Roles[metadata.ROOT]
+	if !ok {
+		return fmt.Errorf("invalid root.json: no root role")
+	}
+	var keyIDs []string
+	for _, id := range role.KeyIDs {
+		key, ok := delegator.Signed.Keys[id]
+		if !ok {
+			continue
+		}
+		public, err := key.ToPublicKey()
+		if err != nil {
+			continue
+		}
+		for _, candidate := range pinned {
+			if equal, ok := public.(interface{ Equal(crypto.PublicKey) bool }); ok && equal.Equal(candidate) {
+				keyIDs = append(keyIDs, id)
+				break
+			}
+		}
+	}
+	if len(keyIDs) == 0 {
+		return fmt.Errorf("none of the %d root key(s) of root.json is a trusted root key", len(role.KeyIDs))
+	}
+	role.KeyIDs = keyIDs
+	if err := delegator.VerifyDelegate(metadata.ROOT, root); err != nil {
+		return fmt.Errorf("root.json is not signed with the trusted root keys: %w", err)
+	}
+	return nil
+}
+
+// ParsePublicKeys returns the public keys of all PEM public keys and unencrypted private keys in data,
+// e.g. of the root keys Secret of a Tuf; other PEM blocks are skipped
+func ParsePublicKeys(data []byte) []crypto.PublicKey {
+	var keys []crypto.PublicKey
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return keys
+		}
+		var key interface{}
+		var err error
+		switch block.Type {
+		case "PUBLIC KEY":
+			key, err = x509.ParsePKIXPublicKey(block.Bytes)
+		case "PRIVATE KEY":
+			key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		case "EC PRIVATE KEY":
+			key, err = x509.ParseECPrivateKey(block.Bytes)
+		case "RSA PRIVATE KEY":
+			key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		default:
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if signer, ok := key.(crypto.Signer); ok {
+			key = signer.Public()
+		}
+		keys = append(keys, key)
+	}
+}
+
+// tufFetcher downloads TUF metadata and targets bound to a context
+type tufFetcher struct {
+	ctx    context.Context
+	client *http.Client
+}
+
+// DownloadFile implements fetcher.Fetcher; HTTP errors are returned as metadata.ErrDownloadHTTP
+// so that the updater recognizes the 404 that ends the root rotation
+func (f *tufFetcher) DownloadFile(url string, maxLength int64, _ time.Duration) ([]byte, error) {
+	req, err := http.NewRequestWithContext(f.ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &metadata.ErrDownloadHTTP{StatusCode: resp.StatusCode, URL: url}
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxLength+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxLength {
+		return nil, &metadata.ErrDownloadLengthMismatch{Msg: fmt.Sprintf("%s is larger than %d bytes", url, maxLength)}
+	}
+	return data, nil
+}
@@ -0,0 +1,100 @@
+package clients
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/petrpinkas/config-examples/pkg/clients/tuftest"
+	"github.com/theupdateframework/go-tuf/v2/metadata"
+)
+
+var _ = Describe("TUF updater", func() {
+	var repo *tuftest.Repository
+
+	BeforeEach(func() {
+		repo = tuftest.New(map[string][]byte{RekorTarget: []byte("rekor key")})
+	})
+
+	// rootJSON returns the root.json served by a repository
+	rootJSON := func(url string) []byte {
+		resp, err := http.Get(url + "/root.json")
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		return data
+	}
+
+	It("should accept a root.json signed with the pinned root keys", func(ctx SpecContext) {
+		server := repo.Serve()
+		DeferCleanup(server.Close)
+
+		up, err := NewTUFUpdater(ctx, nil, server.URL, WithRootKeys(ParsePublicKeys(repo.PublicKeyPEM(metadata.ROOT))...))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(up.GetTopLevelTargets()).To(HaveKey(RekorTarget))
+	})
+
+	It("should reject a root.json that lists a pinned key without its signature", func(ctx SpecContext) {
+		// The root role lists the timestamp key, which signs no root.json
+		timestampKey := repo.Root.Signed.Roles[metadata.TIMESTAMP].KeyIDs[0]
+		Expect(repo.Root.Signed.AddKey(repo.Root.Signed.Keys[timestampKey], metadata.ROOT)).To(Succeed())
+		server := repo.Serve()
+		DeferCleanup(server.Close)
+
+		_, err := NewTUFUpdater(ctx, nil, server.URL, WithRootKeys(ParsePublicKeys(repo.PublicKeyPEM(metadata.TIMESTAMP))...))
+		Expect(err).To(MatchError(ContainSubstring("root.json is not signed with the trusted root keys")))
+	})
+
+	It("should start from a trusted root instead of the served one", func(ctx SpecContext) {
+		server := repo.Serve()
+		DeferCleanup(server.Close)
+		other := tuftest.New(nil).Serve()
+		DeferCleanup(other.Close)
+
+		_, err := NewTUFUpdater(ctx, nil, server.URL, WithTrustedRoot(rootJSON(server.URL)))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = NewTUFUpdater(ctx, nil, server.URL, WithTrustedRoot(rootJSON(other.URL)))
+		Expect(err).To(MatchError(ContainSubstring("failed validation")))
+	})
+
+	It("should parse public and unencrypted private PEM keys", func() {
+		ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).NotTo(HaveOccurred())
+
+		pkcs8, err := x509.MarshalPKCS8PrivateKey(ecKey)
+		Expect(err).NotTo(HaveOccurred())
+		sec1, err := x509.MarshalECPrivateKey(ecKey)
+		Expect(err).NotTo(HaveOccurred())
+		public, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+		Expect(err).NotTo(HaveOccurred())
+		var data []byte
+		for _, block := range []*pem.Block{
+			{Type: "PRIVATE KEY", Bytes: pkcs8},
+			{Type: "EC PRIVATE KEY", Bytes: sec1},
+			{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)},
+			{Type: "PUBLIC KEY", Bytes: public},
+			{Type: "ENCRYPTED PRIVATE KEY", Bytes: []byte("encrypted")},
+			{Type: "PRIVATE KEY", Bytes: []byte("invalid")},
+		} {
+			data = append(data, pem.EncodeToMemory(block)...)
+		}
+
+		keys := ParsePublicKeys(data)
+		Expect(keys).To(HaveLen(4))
+		Expect(ecKey.PublicKey.Equal(keys[0])).To(BeTrue())
+		Expect(ecKey.PublicKey.Equal(keys[1])).To(BeTrue())
+		Expect(rsaKey.PublicKey.Equal(keys[2])).To(BeTrue())
+		Expect(rsaKey.PublicKey.Equal(keys[3])).To(BeTrue())
+		Expect(ParsePublicKeys([]byte("no PEM"))).To(BeEmpty())
+	})
+})
@@ -0,0 +1,107 @@
+// Package tuftest provides a locally generated TUF repository for tests of TUF clients
+package tuftest
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/theupdateframework/go-tuf/v2/metadata"
+)
+
+// Repository is a TUF repository with one ed25519 key per top-level role
+// Change the metadata or replace a signer before Serve, e.g. to produce expired metadata or an invalid signature
+type Repository struct {
+	Root      *metadata.Metadata[metadata.RootType]
+	Timestamp *metadata.Metadata[metadata.TimestampType]
+	Snapshot  *metadata.Metadata[metadata.SnapshotType]
+	Targets   *metadata.Metadata[metadata.TargetsType]
+	// Signers by role name
+	Signers map[string]signature.Signer
+
+	files map[string][]byte
+}
+
+// New creates the metadata of a repository publishing the given target files by name
+func New(targets map[string][]byte) *Repository {
+	expires := time.Now().Add(24 * time.Hour)
+	r := &Repository{
+		Root:      metadata.Root(expires),
+		Timestamp: metadata.Timestamp(expires),
+		Snapshot:  metadata.Snapshot(expires),
+		Targets:   metadata.Targets(expires),
+		Signers:   map[string]signature.Signer{},
+		files:     map[string][]byte{},
+	}
+	for _, role := range []string{metadata.ROOT, metadata.TIMESTAMP, metadata.SNAPSHOT, metadata.TARGETS} {
+		r.Signers[role] = NewSigner()
+		public, err := r.Signers[role].PublicKey()
+		Expect(err).NotTo(HaveOccurred())
+		key, err := metadata.KeyFromPublicKey(public)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Root.Signed.AddKey(key, role)).To(Succeed())
+	}
+	for name, data := range targets {
+		file, err := metadata.TargetFile().FromBytes(name, data, "sha256")
+		Expect(err).NotTo(HaveOccurred())
+		r.Targets.Signed.Targets[name] = file
+		r.files["/targets/"+hex.EncodeToString(file.Hashes["sha256"])+"."+name] = data
+	}
+	return r
+}
+
+// NewSigner returns a signer with a new ed25519 key
+func NewSigner() signature.Signer {
+	_, private, err := ed25519.GenerateKey(nil)
+	Expect(err).NotTo(HaveOccurred())
+	signer, err := signature.LoadSigner(private, crypto.Hash(0))
+	Expect(err).NotTo(HaveOccurred())
+	return signer
+}
+
+// PublicKeyPEM returns the public key of the signer of role as PEM, e.g. for a root keys Secret
+func (r *Repository) PublicKeyPEM(role string) []byte {
+	public, err := r.Signers[role].PublicKey()
+	Expect(err).NotTo(HaveOccurred())
+	der, err := x509.MarshalPKIXPublicKey(public)
+	Expect(err).NotTo(HaveOccurred())
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+// Serve signs the metadata and serves it and the targets with consistent snapshot file names
+// The caller closes the server
+func (r *Repository) Serve() *httptest.Server {
+	files := map[string][]byte{}
+	for path, data := range r.files {
+		files[path] = data
+	}
+	sign := func(role string, sign func(signature.Signer) (*metadata.Signature, error), toBytes func(bool) ([]byte, error), names ...string) {
+		_, err := sign(r.Signers[role])
+		Expect(err).NotTo(HaveOccurred())
+		data, err := toBytes(false)
+		Expect(err).NotTo(HaveOccurred())
+		for _, name := range names {
+			files["/"+name] = data
+		}
+	}
+	sign(metadata.ROOT, r.Root.Sign, r.Root.ToBytes, "root.json", "1.root.json")
+	sign(metadata.TIMESTAMP, r.Timestamp.Sign, r.Timestamp.ToBytes, "timestamp.json")
+	sign(metadata.SNAPSHOT, r.Snapshot.Sign, r.Snapshot.ToBytes, "snapshot.json", "1.snapshot.json")
+	sign(metadata.TARGETS, r.Targets.Sign, r.Targets.ToBytes, "targets.json", "1.targets.json")
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		data, ok := files[req.URL.Path]
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+}
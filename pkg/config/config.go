@@ -2,13 +2,17 @@ package config
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
 
+	"github.com/petrpinkas/config-examples/pkg/apis/rhtas/v1alpha1"
+	"github.com/petrpinkas/config-examples/pkg/secrets"
 	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 // Config represents a generic Kubernetes resource configuration
@@ -22,6 +26,8 @@ type Config struct {
 type RuntimeContext struct {
 	Namespace     string
 	InstanceName  string
+	// ConfOverrides replace values of the conf file, e.g. the OIDC issuer of the selected profile
+	ConfOverrides map[string]string
 	// Future: Timestamp, TestID, etc.
 }
 
@@ -156,6 +162,13 @@ func (c *Config) GetNamespace() string {
 	return ""
 }
 
+// ToTyped converts the config to its typed RHTAS object, e.g. *v1alpha1.Securesign
+// Values of the wrong type, e.g. in negative scenarios, are dropped; returns an error for other kinds
+func (c *Config) ToTyped() (v1alpha1.Object, error) {
+	obj, _, err := v1alpha1.FromUnstructuredLenient(&unstructured.Unstructured{Object: c.Data})
+	return obj, err
+}
+
 // FindConfigFiles finds all YAML config files in a directory
 func FindConfigFiles(dir string) ([]string, error) {
 	var files []string
@@ -174,6 +187,7 @@ func FindConfigFiles(dir string) ([]string, error) {
 }
 
 // LoadConfFile loads a .conf file with key=value pairs
+// Values may be secret references (e.g. secret://env/OIDC_PASSWORD), they are resolved while loading
 // Returns a map of key to value
 func LoadConfFile(filePath string) (map[string]string, error) {
 	data, err := os.ReadFile(filePath)
@@ -201,6 +215,10 @@ func LoadConfFile(filePath string) (map[string]string, error) {
 		if key == "" {
 			return nil, fmt.Errorf("empty key in conf file at line %d", i+1)
 		}
+		value, err = secrets.Resolve(context.Background(), value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid secret in conf file at line %d: %w", i+1, err)
+		}
 
 		config[key] = value
 	}
@@ -219,6 +237,15 @@ func ProcessTemplate(templatePath, confPath, outputPath string, runtimeCtx *Runt
 	if err != nil {
 		return fmt.Errorf("failed to load conf file: %w", err)
 	}
+	if runtimeCtx != nil {
+		for key, value := range runtimeCtx.ConfOverrides {
+			value, err := secrets.Resolve(context.Background(), value)
+			if err != nil {
+				return fmt.Errorf("invalid secret in conf override %s: %w", key, err)
+			}
+			confValues[key] = value
+		}
+	}
 
 	// Replace runtime placeholders in conf values first
 	// This allows conf files to use {{NAMESPACE}}, {{INSTANCE_NAME}}, etc.
@@ -234,6 +261,10 @@ func ProcessTemplate(templatePath, confPath, outputPath string, runtimeCtx *Runt
 	// This must happen before parsing because {{PLACEHOLDER}} is not valid YAML syntax
 	templateDataStr := string(templateData)
 	templateDataStr = replaceRuntimePlaceholdersInString(templateDataStr, runtimeCtx)
+	templateDataStr, err = replaceSecretPlaceholdersInString(templateDataStr)
+	if err != nil {
+		return err
+	}
 
 	// Split by YAML document separator (---) to handle multi-document YAML files
 	documents := splitYAMLDocuments(templateDataStr)
@@ -266,10 +297,13 @@ func ProcessTemplate(templatePath, confPath, outputPath string, runtimeCtx *Runt
 	// Join all documents with --- separator
 	outputData := bytes.Join(processedDocs, []byte("---\n"))
 
-	// Write output file
-	if err := os.WriteFile(outputPath, outputData, 0644); err != nil {
+	// Write output file, readable by the owner only because it may contain resolved secrets
+	if err := os.WriteFile(outputPath, outputData, 0600); err != nil {
 		return fmt.Errorf("failed to write output file: %w", err)
 	}
+	if err := os.Chmod(outputPath, 0600); err != nil {
+		return fmt.Errorf("failed to restrict output file: %w", err)
+	}
 
 	return nil
 }
@@ -342,6 +376,26 @@ func replaceRuntimePlaceholdersInString(content string, runtimeCtx *RuntimeConte
 	})
 }
 
+// secretPlaceholderRegex matches secret references used as placeholders, e.g. {{secret://k8s/keycloak-system/keycloak/password}}
+var secretPlaceholderRegex = regexp.MustCompile(`\{\{(secret://[^{}\s]+)\}\}`)
+
+// replaceSecretPlaceholdersInString replaces {{secret://...}} placeholders in a YAML string with the resolved secrets
+func replaceSecretPlaceholdersInString(content string) (string, error) {
+	var errs []string
+	content = secretPlaceholderRegex.ReplaceAllStringFunc(content, func(match string) string {
+		value, err := secrets.Resolve(context.Background(), strings.Trim(match, "{}"))
+		if err != nil {
+			errs = append(errs, err.Error())
+			return match
+		}
+		return value
+	})
+	if len(errs) > 0 {
+		return "", fmt.Errorf("failed to resolve secret placeholders: %s", strings.Join(errs, "; "))
+	}
+	return content, nil
+}
+
 // replaceRuntimePlaceholdersInMap replaces {{PLACEHOLDER}} patterns in a map of strings
 // This is used to process conf file values that may contain runtime placeholders
 func replaceRuntimePlaceholdersInMap(values map[string]string, runtimeCtx *RuntimeContext) map[string]string {
@@ -432,6 +486,12 @@ func ProcessTemplateFromPaths(scenarioDir, scenarioName, variantName string, run
 //   - configPath: Path to the generated YAML configuration file
 //   - error: Any error encountered during processing
 func ProcessScenarioTemplate(scenarioName, scenariosDir, namespace, instanceName, variantName string) (string, error) {
+	return ProcessScenarioTemplateWithOverrides(scenarioName, scenariosDir, namespace, instanceName, variantName, nil)
+}
+
+// ProcessScenarioTemplateWithOverrides is ProcessScenarioTemplate with conf values that replace those of the conf file,
+// e.g. the conf overrides of the selected profile
+func ProcessScenarioTemplateWithOverrides(scenarioName, scenariosDir, namespace, instanceName, variantName string, overrides map[string]string) (string, error) {
 	scenarioDir := filepath.Join(scenariosDir, scenarioName)
 	// Extract folder name (prefix) from scenariosDir path
 	// e.g., "../../scenarios/rhtas" -> "rhtas", "../../scenarios/ctlog" -> "ctlog"
@@ -439,8 +499,9 @@ func ProcessScenarioTemplate(scenarioName, scenariosDir, namespace, instanceName
 	baseName := fmt.Sprintf("%s-%s", folderName, scenarioName)
 
 	runtimeCtx := &RuntimeContext{
-		Namespace:    namespace,
-		InstanceName: instanceName,
+		Namespace:     namespace,
+		InstanceName:  instanceName,
+		ConfOverrides: overrides,
 	}
 
 	configPath, err := ProcessTemplateFromPaths(scenarioDir, baseName, variantName, runtimeCtx)
@@ -6,6 +6,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/petrpinkas/config-examples/pkg/apis/rhtas/v1alpha1"
 )
 
 // Template tests are included in TestConfig suite
@@ -70,6 +71,25 @@ IssuerURL=https://keycloak.example.com
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("invalid format"))
 		})
+
+		It("should resolve secret references", func() {
+			GinkgoT().Setenv("TEMPLATE_TEST_CLIENT_SECRET", "client-secret")
+			confContent := `ClientID=trusted-artifact-signer
+ClientSecret=secret://env/TEMPLATE_TEST_CLIENT_SECRET
+`
+			err := os.WriteFile(confPath, []byte(confContent), 0644)
+			Expect(err).NotTo(HaveOccurred())
+
+			conf, err := LoadConfFile(confPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(conf["ClientID"]).To(Equal("trusted-artifact-signer"))
+			Expect(conf["ClientSecret"]).To(Equal("client-secret"))
+
+			err = os.WriteFile(confPath, []byte("ClientSecret=secret://env/TEMPLATE_TEST_MISSING\n"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = LoadConfFile(confPath)
+			Expect(err).To(MatchError(ContainSubstring("invalid secret in conf file at line 1")))
+		})
 	})
 
 	Describe("ProcessTemplate", func() {
@@ -125,6 +145,98 @@ IssuerURL=https://keycloak.example.com/auth/realms/rhtas
 			Expect(issuerMap["IssuerURL"]).To(Equal("https://keycloak.example.com/auth/realms/rhtas"))
 		})
 
+		It("should replace conf values with the conf overrides", func() {
+			templateContent := `kind: Securesign
+spec:
+  fulcio:
+    config:
+      OIDCIssuers:
+        - Issuer: 'https://your-oidc-issuer-url'
+          IssuerURL: 'https://your-oidc-issuer-url'
+`
+			err := os.WriteFile(templatePath, []byte(templateContent), 0644)
+			Expect(err).NotTo(HaveOccurred())
+			confContent := `Issuer=https://keycloak.example.com/auth/realms/rhtas
+IssuerURL=https://keycloak.example.com/auth/realms/rhtas
+`
+			err = os.WriteFile(confPath, []byte(confContent), 0644)
+			Expect(err).NotTo(HaveOccurred())
+
+			runtimeCtx := &RuntimeContext{
+				ConfOverrides: map[string]string{"Issuer": "https://keycloak.{{NAMESPACE}}.example.com/auth/realms/rhtas"},
+				Namespace:     "profile",
+			}
+			err = ProcessTemplate(templatePath, confPath, outputPath, runtimeCtx)
+			Expect(err).NotTo(HaveOccurred())
+
+			outputConfig, err := LoadConfig(outputPath)
+			Expect(err).NotTo(HaveOccurred())
+			issuers := outputConfig.Data["spec"].(map[string]interface{})["fulcio"].(map[string]interface{})["config"].(map[string]interface{})["OIDCIssuers"].([]interface{})
+			issuerMap := issuers[0].(map[string]interface{})
+			Expect(issuerMap["Issuer"]).To(Equal("https://keycloak.profile.example.com/auth/realms/rhtas"))
+			Expect(issuerMap["IssuerURL"]).To(Equal("https://keycloak.example.com/auth/realms/rhtas"))
+		})
+
+		It("should convert the processed template to typed objects", func() {
+			templateContent := `kind: Fulcio
+apiVersion: rhtas.redhat.com/v1alpha1
+metadata:
+  name: fulcio-sample
+  namespace: '{{NAMESPACE}}'
+spec:
+  config:
+    OIDCIssuers:
+      - ClientID: trusted-artifact-signer
+        Issuer: 'https://your-oidc-issuer-url'
+        IssuerURL: 'https://your-oidc-issuer-url'
+        Type: email
+  ctlog:
+    port: 80
+`
+			err := os.WriteFile(templatePath, []byte(templateContent), 0644)
+			Expect(err).NotTo(HaveOccurred())
+			err = os.WriteFile(confPath, []byte("Issuer=https://keycloak.example.com/auth/realms/rhtas\n"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = ProcessTemplate(templatePath, confPath, outputPath, &RuntimeContext{Namespace: "typed"})
+			Expect(err).NotTo(HaveOccurred())
+			outputConfig, err := LoadConfig(outputPath)
+			Expect(err).NotTo(HaveOccurred())
+			obj, err := outputConfig.ToTyped()
+			Expect(err).NotTo(HaveOccurred())
+			fulcio, ok := obj.(*v1alpha1.Fulcio)
+			Expect(ok).To(BeTrue())
+			Expect(fulcio.Namespace).To(Equal("typed"))
+			Expect(fulcio.Spec.Config.OIDCIssuers[0].Issuer).To(Equal("https://keycloak.example.com/auth/realms/rhtas"))
+			Expect(*fulcio.Spec.Ctlog.Port).To(BeEquivalentTo(80))
+		})
+
+		It("should replace secret placeholders", func() {
+			GinkgoT().Setenv("TEMPLATE_TEST_DB_PASSWORD", "db-password")
+			templateContent := `kind: Securesign
+spec:
+  trillian:
+    database:
+      password: '{{secret://env/TEMPLATE_TEST_DB_PASSWORD}}'
+`
+			err := os.WriteFile(templatePath, []byte(templateContent), 0644)
+			Expect(err).NotTo(HaveOccurred())
+			err = os.WriteFile(confPath, []byte(""), 0644)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = ProcessTemplate(templatePath, confPath, outputPath, nil)
+			Expect(err).NotTo(HaveOccurred())
+			outputConfig, err := LoadConfig(outputPath)
+			Expect(err).NotTo(HaveOccurred())
+			database := outputConfig.Data["spec"].(map[string]interface{})["trillian"].(map[string]interface{})["database"].(map[string]interface{})
+			Expect(database["password"]).To(Equal("db-password"))
+
+			err = os.WriteFile(templatePath, []byte("password: '{{secret://env/TEMPLATE_TEST_MISSING}}'\n"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+			err = ProcessTemplate(templatePath, confPath, outputPath, nil)
+			Expect(err).To(MatchError(ContainSubstring("environment variable TEMPLATE_TEST_MISSING is not set")))
+		})
+
 		It("should replace runtime placeholders {{NAMESPACE}} and {{INSTANCE_NAME}}", func() {
 			// Create template file with runtime placeholders
 			templateContent := `kind: Securesign
@@ -0,0 +1,221 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/petrpinkas/config-examples/pkg/config"
+	"github.com/pmezard/go-difflib/difflib"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// serverMetadataFields are metadata fields set by the API server that never appear in a rendered scenario
+var serverMetadataFields = []string{
+	"managedFields",
+	"resourceVersion",
+	"uid",
+	"creationTimestamp",
+	"generation",
+	"selfLink",
+}
+
+// DocumentDiff holds the difference between a rendered document and the live object
+type DocumentDiff struct {
+	// Index is the 1-based position of the document in the source
+	Index     int
+	Kind      string
+	Namespace string
+	Name      string
+	// Exists is false when the object is not present in the cluster yet
+	Exists bool
+	// Diff is a unified diff from the live object to the rendered document, empty if they match
+	Diff string
+}
+
+// HasChanges returns true if re-applying the document would change the cluster
+func (d DocumentDiff) HasChanges() bool {
+	return !d.Exists || d.Diff != ""
+}
+
+// DiffConfig compares every document of a configuration with the live object in the cluster
+// It reads the documents the same way as InstallConfig
+// Use WithIgnoreDefaults() to hide status, server metadata and fields defaulted by the operator
+func DiffConfig(ctx context.Context, cli client.Client, cfg *config.Config, filePath string, opts ...Option) ([]DocumentDiff, error) {
+	src, err := sourceFor(cfg, filePath, newOptions(opts...))
+	if err != nil {
+		return nil, err
+	}
+
+	return DiffSource(ctx, cli, src, opts...)
+}
+
+// DiffSource compares every document provided by a Source with the live object in the cluster
+func DiffSource(ctx context.Context, cli client.Client, src Source, opts ...Option) ([]DocumentDiff, error) {
+	documents, err := src.Documents()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", src, err)
+	}
+
+	return DiffDocuments(ctx, cli, documents, opts...)
+}
+
+// DiffDocuments fetches the live object for each YAML document and produces a unified diff
+// Diffs are returned in source order
+func DiffDocuments(ctx context.Context, cli client.Client, documents []string, opts ...Option) ([]DocumentDiff, error) {
+	options := newOptions(opts...)
+
+	parsed, err := parseDocuments(documents)
+	if err != nil {
+		return nil, err
+	}
+	// Compare against the objects the installer would actually write
+	if err := resolveNamespaces(cli.RESTMapper(), parsed, options); err != nil {
+		return nil, err
+	}
+
+	var diffs []DocumentDiff
+	for _, doc := range parsed {
+		diff, err := diffObject(ctx, cli, doc.index, doc.obj, options)
+		if err != nil {
+			return diffs, err
+		}
+		diffs = append(diffs, diff)
+	}
+
+	return diffs, nil
+}
+
+// FormatDiffs renders diffs in a single report, documents without changes are skipped
+func FormatDiffs(diffs []DocumentDiff) string {
+	var sb strings.Builder
+	for _, d := range diffs {
+		if !d.HasChanges() {
+			continue
+		}
+		if !d.Exists {
+			fmt.Fprintf(&sb, "# document %d %s %s/%s: not found in cluster, would be created\n", d.Index, d.Kind, d.Namespace, d.Name)
+		}
+		sb.WriteString(d.Diff)
+	}
+	return sb.String()
+}
+
+// diffObject compares a rendered object with its live counterpart
+func diffObject(ctx context.Context, cli client.Client, index int, obj *unstructured.Unstructured, options *Options) (DocumentDiff, error) {
+	result := DocumentDiff{
+		Index:     index,
+		Kind:      obj.GetKind(),
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+	}
+
+	live := &unstructured.Unstructured{}
+	live.SetGroupVersionKind(obj.GroupVersionKind())
+	err := cli.Get(ctx, client.ObjectKey{
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+	}, live)
+
+	var liveObject map[string]interface{}
+	if errors.IsNotFound(err) {
+		liveObject = map[string]interface{}{}
+	} else if err != nil {
+		return result, fmt.Errorf("failed to get live resource %d (%s/%s): %w", index, obj.GetKind(), obj.GetName(), err)
+	} else {
+		result.Exists = true
+		liveObject = live.Object
+		if options.IgnoreDefaults {
+			liveObject = normalizeLive(liveObject, obj.Object)
+		}
+	}
+
+	liveYAML, err := toDiffYAML(liveObject)
+	if err != nil {
+		return result, fmt.Errorf("failed to marshal live resource %d: %w", index, err)
+	}
+	renderedYAML, err := toDiffYAML(obj.Object)
+	if err != nil {
+		return result, fmt.Errorf("failed to marshal rendered resource %d: %w", index, err)
+	}
+
+	name := fmt.Sprintf("%s/%s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+	result.Diff, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(liveYAML),
+		B:        difflib.SplitLines(renderedYAML),
+		FromFile: "live/" + name,
+		ToFile:   "rendered/" + name,
+		Context:  3,
+	})
+	if err != nil {
+		return result, fmt.Errorf("failed to diff resource %d: %w", index, err)
+	}
+
+	return result, nil
+}
+
+// toDiffYAML marshals an object with sorted keys so that diffs are stable
+func toDiffYAML(obj map[string]interface{}) (string, error) {
+	if len(obj) == 0 {
+		return "", nil
+	}
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// normalizeLive removes status and server metadata from the live object
+// and prunes every field that is not present in the rendered object (operator defaults)
+func normalizeLive(live, rendered map[string]interface{}) map[string]interface{} {
+	live = deepCopyObject(live)
+	delete(live, "status")
+	if metadata, ok := live["metadata"].(map[string]interface{}); ok {
+		for _, field := range serverMetadataFields {
+			delete(metadata, field)
+		}
+	}
+
+	pruned, _ := pruneToShape(live, rendered).(map[string]interface{})
+	return pruned
+}
+
+// pruneToShape keeps only the parts of live that also exist in rendered
+// Lists are pruned element by element when both sides have the same length
+func pruneToShape(live, rendered interface{}) interface{} {
+	switch r := rendered.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			return live
+		}
+		result := make(map[string]interface{}, len(r))
+		for key, rendValue := range r {
+			if liveValue, exists := l[key]; exists {
+				result[key] = pruneToShape(liveValue, rendValue)
+			}
+		}
+		return result
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok || len(l) != len(r) {
+			return live
+		}
+		result := make([]interface{}, len(l))
+		for i := range l {
+			result[i] = pruneToShape(l[i], r[i])
+		}
+		return result
+	default:
+		return live
+	}
+}
+
+// deepCopyObject returns a deep copy of an unstructured object map
+func deepCopyObject(obj map[string]interface{}) map[string]interface{} {
+	return (&unstructured.Unstructured{Object: obj}).DeepCopy().Object
+}
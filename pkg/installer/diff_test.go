@@ -0,0 +1,93 @@
+package installer
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const configMapYAML = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+  namespace: test-ns
+  labels:
+    app: demo
+data:
+  key: one
+`
+
+// liveConfigMap returns a ConfigMap with the given value and an operator-added field
+func liveConfigMap(value string) *v1.ConfigMap {
+	cm := &v1.ConfigMap{}
+	cm.Namespace = "test-ns"
+	cm.Name = "first"
+	cm.Labels = map[string]string{"app": "demo", "operator/added": "true"}
+	cm.Data = map[string]string{"key": value, "defaulted": "by-operator"}
+	return cm
+}
+
+var _ = Describe("Diff", func() {
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("should report documents that do not exist in the cluster", func() {
+		cli := fake.NewClientBuilder().Build()
+		diffs, err := DiffDocuments(ctx, cli, []string{configMapYAML})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diffs).To(HaveLen(1))
+		Expect(diffs[0].Exists).To(BeFalse())
+		Expect(diffs[0].HasChanges()).To(BeTrue())
+		Expect(diffs[0].Diff).To(ContainSubstring("+  key: one"))
+	})
+
+	It("should ignore server and operator fields when requested", func() {
+		cli := fake.NewClientBuilder().WithObjects(liveConfigMap("one")).Build()
+		diffs, err := DiffDocuments(ctx, cli, []string{configMapYAML}, WithIgnoreDefaults())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diffs).To(HaveLen(1))
+		Expect(diffs[0].Exists).To(BeTrue())
+		Expect(diffs[0].Diff).To(BeEmpty())
+		Expect(FormatDiffs(diffs)).To(BeEmpty())
+	})
+
+	It("should show server and operator fields in full mode", func() {
+		cli := fake.NewClientBuilder().WithObjects(liveConfigMap("one")).Build()
+		diffs, err := DiffDocuments(ctx, cli, []string{configMapYAML})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diffs[0].Diff).To(ContainSubstring("-  defaulted: by-operator"))
+		Expect(diffs[0].Diff).To(ContainSubstring("resourceVersion"))
+	})
+
+	It("should show changed values as a unified diff", func() {
+		cli := fake.NewClientBuilder().WithObjects(liveConfigMap("old")).Build()
+		diffs, err := DiffDocuments(ctx, cli, []string{configMapYAML}, WithIgnoreDefaults())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diffs[0].HasChanges()).To(BeTrue())
+		Expect(diffs[0].Diff).To(ContainSubstring("--- live/ConfigMap/test-ns/first"))
+		Expect(diffs[0].Diff).To(ContainSubstring("+++ rendered/ConfigMap/test-ns/first"))
+		Expect(diffs[0].Diff).To(ContainSubstring("-  key: old"))
+		Expect(diffs[0].Diff).To(ContainSubstring("+  key: one"))
+		Expect(diffs[0].Diff).NotTo(ContainSubstring("defaulted"))
+	})
+
+	It("should scope documents with the namespace and cluster-scoped options", func() {
+		cli := fake.NewClientBuilder().Build()
+		documents := []string{noNamespaceYAML, namespaceYAML}
+		_, err := DiffDocuments(ctx, cli, documents)
+		Expect(err).To(MatchError(ContainSubstring("namespace is not set")))
+		Expect(err).To(MatchError(ContainSubstring("cluster-scoped resources are not allowed")))
+
+		diffs, err := DiffDocuments(ctx, cli, documents, WithNamespace("target"), WithClusterScoped())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diffs).To(HaveLen(2))
+		Expect(diffs[0].Namespace).To(Equal("target"))
+		Expect(diffs[1].Namespace).To(BeEmpty())
+	})
+})
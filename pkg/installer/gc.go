@@ -0,0 +1,73 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NamespaceGVK is the GroupVersionKind of core Namespaces
+var NamespaceGVK = schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}
+
+// FindStale returns all resources created by this suite whose run is older than ttl
+// Namespaces are always searched, gvks lists additional kinds (e.g., Securesign) to search across all namespaces
+// Kinds that are not registered in the cluster are skipped
+// Resources of the given kinds are returned before namespaces so they can be deleted in order
+func FindStale(ctx context.Context, cli client.Client, ttl time.Duration, gvks ...schema.GroupVersionKind) ([]*unstructured.Unstructured, error) {
+	cutoff := time.Now().Add(-ttl)
+
+	kinds := append([]schema.GroupVersionKind{}, gvks...)
+	kinds = append(kinds, NamespaceGVK)
+
+	var stale []*unstructured.Unstructured
+	for _, gvk := range kinds {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+
+		err := cli.List(ctx, list, client.MatchingLabels{LabelManagedBy: ManagedByValue})
+		if meta.IsNoMatchError(err) {
+			continue // CRD not installed
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s resources: %w", gvk.Kind, err)
+		}
+
+		for i := range list.Items {
+			obj := &list.Items[i]
+			createdAt, ok := CreatedAt(obj)
+			if !ok || createdAt.After(cutoff) {
+				continue
+			}
+			stale = append(stale, obj)
+		}
+	}
+
+	return stale, nil
+}
+
+// CollectGarbage deletes all resources returned by FindStale
+// Resources that are already gone are ignored
+// Returns the resources that were deleted
+func CollectGarbage(ctx context.Context, cli client.Client, ttl time.Duration, gvks ...schema.GroupVersionKind) ([]*unstructured.Unstructured, error) {
+	stale, err := FindStale(ctx, cli, ttl, gvks...)
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []*unstructured.Unstructured
+	for _, obj := range stale {
+		if err := cli.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) {
+			return deleted, fmt.Errorf("failed to delete %s %s/%s (run %s): %w",
+				obj.GetKind(), obj.GetNamespace(), obj.GetName(), obj.GetLabels()[LabelRunID], err)
+		}
+		deleted = append(deleted, obj)
+	}
+
+	return deleted, nil
+}
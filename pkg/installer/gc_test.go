@@ -0,0 +1,114 @@
+package installer
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var configMapGVK = schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+
+// ownedNamespace returns a namespace stamped by a run that started age ago
+func ownedNamespace(name, runID string, age time.Duration) *v1.Namespace {
+	ns := &v1.Namespace{}
+	ns.Name = name
+	Ownership{RunID: runID, CreatedAt: time.Now().Add(-age)}.Apply(ns)
+	return ns
+}
+
+var _ = Describe("Ownership", func() {
+	It("should stamp run labels and the creation timestamp", func() {
+		createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		cm := &v1.ConfigMap{}
+		cm.Labels = map[string]string{"app": "demo"}
+		Ownership{
+			RunID:       "run-1",
+			Scenario:    "default",
+			Variant:     "base",
+			CreatedAt:   createdAt,
+			Labels:      map[string]string{"team": "qe"},
+			Annotations: map[string]string{"note": "hello"},
+		}.Apply(cm)
+
+		Expect(cm.Labels).To(Equal(map[string]string{
+			"app":          "demo",
+			"team":         "qe",
+			LabelManagedBy: ManagedByValue,
+			LabelRunID:     "run-1",
+			LabelScenario:  "default",
+			LabelVariant:   "base",
+		}))
+		Expect(cm.Annotations).To(HaveKeyWithValue("note", "hello"))
+		parsed, ok := CreatedAt(cm)
+		Expect(ok).To(BeTrue())
+		Expect(parsed).To(BeTemporally("==", createdAt))
+	})
+
+	It("should generate label-safe run IDs", func() {
+		Expect(NewRunID()).To(MatchRegexp(`^\d{8}-\d{6}-[0-9a-f]{6}$`))
+		Expect(NewRunID()).NotTo(Equal(NewRunID()))
+	})
+
+	It("should stamp installed documents", func() {
+		cli := fake.NewClientBuilder().Build()
+		_, err := ApplyDocuments(context.Background(), cli, []string{configMapYAML},
+			WithOwnership(Ownership{RunID: "run-1", Scenario: "default"}))
+		Expect(err).NotTo(HaveOccurred())
+
+		cm := &v1.ConfigMap{}
+		Expect(cli.Get(context.Background(), client.ObjectKey{Namespace: "test-ns", Name: "first"}, cm)).To(Succeed())
+		Expect(cm.Labels).To(HaveKeyWithValue("app", "demo"))
+		Expect(cm.Labels).To(HaveKeyWithValue(LabelRunID, "run-1"))
+		Expect(cm.Labels).To(HaveKeyWithValue(LabelScenario, "default"))
+		Expect(cm.Annotations).To(HaveKey(AnnotationCreatedAt))
+	})
+})
+
+var _ = Describe("Garbage collection", func() {
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("should delete only resources from runs older than the TTL", func() {
+		oldCM := &v1.ConfigMap{}
+		oldCM.Namespace = "old-ns"
+		oldCM.Name = "old"
+		Ownership{RunID: "old-run", CreatedAt: time.Now().Add(-48 * time.Hour)}.Apply(oldCM)
+
+		unmanaged := &v1.Namespace{}
+		unmanaged.Name = "unmanaged"
+
+		cli := fake.NewClientBuilder().WithObjects(
+			ownedNamespace("old-ns", "old-run", 48*time.Hour),
+			ownedNamespace("new-ns", "new-run", time.Minute),
+			oldCM,
+			unmanaged,
+		).Build()
+
+		stale, err := FindStale(ctx, cli, 24*time.Hour, configMapGVK)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stale).To(HaveLen(2))
+		// Resources of the given kinds come before namespaces
+		Expect(stale[0].GetKind()).To(Equal("ConfigMap"))
+		Expect(stale[1].GetKind()).To(Equal("Namespace"))
+		Expect(stale[1].GetName()).To(Equal("old-ns"))
+
+		deleted, err := CollectGarbage(ctx, cli, 24*time.Hour, configMapGVK)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deleted).To(HaveLen(2))
+
+		err = cli.Get(ctx, client.ObjectKey{Name: "old-ns"}, &v1.Namespace{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "new-ns"}, &v1.Namespace{})).To(Succeed())
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "unmanaged"}, &v1.Namespace{})).To(Succeed())
+	})
+})
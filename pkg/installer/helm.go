@@ -0,0 +1,106 @@
+package installer
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+)
+
+// HelmOptions configures how a local Helm chart is rendered
+type HelmOptions struct {
+	// ReleaseName is exposed to templates as .Release.Name, defaults to the chart name
+	ReleaseName string
+	// Namespace is exposed to templates as .Release.Namespace, defaults to "default"
+	Namespace string
+	// ValuesFiles are merged in order, later files take precedence over earlier ones and chart defaults
+	ValuesFiles []string
+}
+
+// HelmSource returns a Source that renders a locally vendored Helm chart in-process
+// It is the equivalent of `helm template` without any repository or cluster access
+// CRDs from the chart's crds/ directory are returned before the rendered templates
+func HelmSource(chartPath string, options HelmOptions) Source {
+	return helmSource{chartPath: chartPath, options: options}
+}
+
+type helmSource struct {
+	chartPath string
+	options   HelmOptions
+}
+
+func (s helmSource) Documents() ([]string, error) {
+	chart, err := loader.Load(s.chartPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart: %w", err)
+	}
+
+	values := map[string]interface{}{}
+	for _, file := range s.options.ValuesFiles {
+		fileValues, err := chartutil.ReadValuesFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read values file %s: %w", file, err)
+		}
+		values = chartutil.CoalesceTables(fileValues.AsMap(), values)
+	}
+
+	if err := chartutil.ProcessDependenciesWithMerge(chart, values); err != nil {
+		return nil, fmt.Errorf("failed to process chart dependencies: %w", err)
+	}
+
+	releaseName := s.options.ReleaseName
+	if releaseName == "" {
+		releaseName = chart.Name()
+	}
+	namespace := s.options.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	renderValues, err := chartutil.ToRenderValues(chart, values, chartutil.ReleaseOptions{
+		Name:      releaseName,
+		Namespace: namespace,
+		Revision:  1,
+		IsInstall: true,
+	}, chartutil.DefaultCapabilities)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute chart values: %w", err)
+	}
+
+	rendered, err := engine.Render(chart, renderValues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render chart: %w", err)
+	}
+
+	var documents []string
+	for _, crd := range chart.CRDObjects() {
+		documents = append(documents, splitYAMLDocuments(string(crd.File.Data))...)
+	}
+
+	// Render output is a map, sort by file name for a stable order
+	names := make([]string, 0, len(rendered))
+	for name := range rendered {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		base := path.Base(name)
+		if strings.HasPrefix(base, "_") || base == "NOTES.txt" {
+			continue // Partials and notes are not manifests
+		}
+		if strings.TrimSpace(rendered[name]) == "" {
+			continue
+		}
+		documents = append(documents, splitYAMLDocuments(rendered[name])...)
+	}
+	return documents, nil
+}
+
+func (s helmSource) String() string {
+	return "chart " + s.chartPath
+}
@@ -3,7 +3,6 @@ package installer
 import (
 	"context"
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/petrpinkas/config-examples/pkg/config"
@@ -13,32 +12,122 @@ import (
 	"sigs.k8s.io/yaml"
 )
 
+// Operation describes what the installer did with a document
+type Operation string
+
+const (
+	// OperationCreated means the resource did not exist and was created
+	OperationCreated Operation = "created"
+	// OperationUpdated means the resource already existed and was updated
+	OperationUpdated Operation = "updated"
+)
+
+// Result holds the outcome of applying a single YAML document
+type Result struct {
+	// Index is the 1-based position of the document in the source
+	Index     int
+	Kind      string
+	Namespace string
+	Name      string
+	Operation Operation
+	// DryRun is true when the document was only validated by the API server
+	DryRun bool
+	// Err is the error returned by the API server for this document, if any
+	Err error
+}
+
+// String returns a short human readable description of the result
+func (r Result) String() string {
+	prefix := ""
+	if r.DryRun {
+		prefix = "(dry run) "
+	}
+	if r.Err != nil {
+		return fmt.Sprintf("%sdocument %d %s %s/%s: %v", prefix, r.Index, r.Kind, r.Namespace, r.Name, r.Err)
+	}
+	return fmt.Sprintf("%sdocument %d %s %s/%s: %s", prefix, r.Index, r.Kind, r.Namespace, r.Name, r.Operation)
+}
+
 // InstallConfig installs a configuration to the cluster
 // It supports both single and multi-document YAML files (separated by ---)
 // If filePath is provided, it reads directly from the file to preserve multi-document structure
+// filePath may also point to a directory of manifests, a kustomization or a Helm chart (see SourceForPath)
 // Otherwise, it uses cfg.ToYAML() which only contains the first document
-func InstallConfig(ctx context.Context, cli client.Client, cfg *config.Config, filePath string) error {
-	var yamlData []byte
-	var err error
-
-	// If filePath is provided, read directly from file to preserve multi-document structure
-	if filePath != "" {
-		yamlData, err = os.ReadFile(filePath)
-		if err != nil {
-			return fmt.Errorf("failed to read config file: %w", err)
-		}
-	} else {
-		// Fallback to ToYAML() which only contains the first document
-		yamlData, err = cfg.ToYAML()
-		if err != nil {
-			return fmt.Errorf("failed to convert config to YAML: %w", err)
+// Options such as WithDryRun() change how the documents are sent to the API server
+func InstallConfig(ctx context.Context, cli client.Client, cfg *config.Config, filePath string, opts ...Option) error {
+	src, err := sourceFor(cfg, filePath, newOptions(opts...))
+	if err != nil {
+		return err
+	}
+
+	_, err = InstallSource(ctx, cli, src, opts...)
+	return err
+}
+
+// InstallSource installs all documents provided by a Source
+func InstallSource(ctx context.Context, cli client.Client, src Source, opts ...Option) ([]Result, error) {
+	documents, err := src.Documents()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", src, err)
+	}
+
+	return ApplyDocuments(ctx, cli, documents, opts...)
+}
+
+// ValidateConfig sends every document of a configuration to the API server with dryRun=All
+// Nothing is persisted; admission webhook and OpenAPI validation errors are reported per document
+// The returned error aggregates all failing documents, the results contain one entry per document
+func ValidateConfig(ctx context.Context, cli client.Client, cfg *config.Config, filePath string, opts ...Option) ([]Result, error) {
+	src, err := sourceFor(cfg, filePath, newOptions(opts...))
+	if err != nil {
+		return nil, err
+	}
+
+	return InstallSource(ctx, cli, src, append(opts, WithDryRun())...)
+}
+
+// ApplyDocuments creates or updates every YAML document in the cluster
+// Documents are installed in dependency order (namespaces, CRDs, secrets, ... before custom resources)
+// Namespaces are checked first, see WithNamespace() and WithClusterScoped()
+// In normal mode it stops at the first failing document
+// In dry run mode it keeps going so that all validation errors are collected
+func ApplyDocuments(ctx context.Context, cli client.Client, documents []string, opts ...Option) ([]Result, error) {
+	options := newOptions(opts...)
+
+	parsed, err := parseDocuments(documents)
+	if err != nil {
+		return nil, err
+	}
+	if err := resolveNamespaces(cli.RESTMapper(), parsed, options); err != nil {
+		return nil, err
+	}
+	sortDocuments(parsed)
+
+	var results []Result
+	var failed []string
+
+	for _, doc := range parsed {
+		result := applyObject(ctx, cli, doc.index, doc.obj, options)
+		results = append(results, result)
+
+		if result.Err != nil {
+			if !options.DryRun {
+				return results, result.Err
+			}
+			failed = append(failed, result.Err.Error())
 		}
 	}
 
-	// Split multi-document YAML into individual documents
-	documents := splitYAMLDocuments(string(yamlData))
+	if len(failed) > 0 {
+		return results, fmt.Errorf("server-side validation failed for %d document(s):\n%s", len(failed), strings.Join(failed, "\n"))
+	}
+	return results, nil
+}
 
-	// Install each document as a separate resource
+// parseDocuments unmarshals YAML documents into unstructured objects
+// Empty documents and documents with only comments are skipped
+func parseDocuments(documents []string) ([]document, error) {
+	var parsed []document
 	for i, doc := range documents {
 		if strings.TrimSpace(doc) == "" {
 			continue // Skip empty documents
@@ -47,34 +136,63 @@ func InstallConfig(ctx context.Context, cli client.Client, cfg *config.Config, f
 		// Unmarshal YAML into unstructured object
 		obj := &unstructured.Unstructured{}
 		if err := yaml.Unmarshal([]byte(doc), &obj.Object); err != nil {
-			return fmt.Errorf("failed to unmarshal YAML document %d: %w", i+1, err)
+			return nil, fmt.Errorf("failed to unmarshal YAML document %d: %w", i+1, err)
+		}
+		if len(obj.Object) == 0 {
+			continue // Skip documents with only comments
 		}
+		parsed = append(parsed, document{index: i + 1, obj: obj})
+	}
+	return parsed, nil
+}
 
-		// Apply the object (Create or Update)
-		existing := &unstructured.Unstructured{}
-		existing.SetGroupVersionKind(obj.GroupVersionKind())
-		err = cli.Get(ctx, client.ObjectKey{
-			Namespace: obj.GetNamespace(),
-			Name:      obj.GetName(),
-		}, existing)
-
-		if errors.IsNotFound(err) {
-			// Create new resource
-			if err := cli.Create(ctx, obj); err != nil {
-				return fmt.Errorf("failed to create resource %d (%s/%s): %w", i+1, obj.GetKind(), obj.GetName(), err)
-			}
-		} else if err == nil {
-			// Update existing resource
-			obj.SetResourceVersion(existing.GetResourceVersion())
-			if err := cli.Update(ctx, obj); err != nil {
-				return fmt.Errorf("failed to update resource %d (%s/%s): %w", i+1, obj.GetKind(), obj.GetName(), err)
-			}
-		} else {
-			return fmt.Errorf("failed to check if resource %d exists: %w", i+1, err)
+// applyObject creates the object or updates it if it already exists
+func applyObject(ctx context.Context, cli client.Client, index int, obj *unstructured.Unstructured, options *Options) Result {
+	result := Result{
+		Index:     index,
+		Kind:      obj.GetKind(),
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		DryRun:    options.DryRun,
+	}
+
+	if options.Ownership != nil {
+		options.Ownership.Apply(obj)
+	}
+
+	var createOpts []client.CreateOption
+	var updateOpts []client.UpdateOption
+	if options.DryRun {
+		createOpts = append(createOpts, client.DryRunAll)
+		updateOpts = append(updateOpts, client.DryRunAll)
+	}
+
+	// Apply the object (Create or Update)
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(obj.GroupVersionKind())
+	err := cli.Get(ctx, client.ObjectKey{
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+	}, existing)
+
+	if errors.IsNotFound(err) {
+		// Create new resource
+		result.Operation = OperationCreated
+		if err := cli.Create(ctx, obj, createOpts...); err != nil {
+			result.Err = fmt.Errorf("failed to create resource %d (%s/%s): %w", index, obj.GetKind(), obj.GetName(), err)
+		}
+	} else if err == nil {
+		// Update existing resource
+		result.Operation = OperationUpdated
+		obj.SetResourceVersion(existing.GetResourceVersion())
+		if err := cli.Update(ctx, obj, updateOpts...); err != nil {
+			result.Err = fmt.Errorf("failed to update resource %d (%s/%s): %w", index, obj.GetKind(), obj.GetName(), err)
 		}
+	} else {
+		result.Err = fmt.Errorf("failed to check if resource %d exists: %w", index, err)
 	}
 
-	return nil
+	return result
 }
 
 // splitYAMLDocuments splits a multi-document YAML string into individual documents
@@ -100,7 +218,7 @@ func splitYAMLDocuments(content string) []string {
 			continue
 		}
 		currentDoc = append(currentDoc, line)
-		
+
 		// If this is the last line, save the current document
 		if i == len(lines)-1 && len(currentDoc) > 0 {
 			docStr := strings.Join(currentDoc, "\n")
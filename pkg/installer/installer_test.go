@@ -0,0 +1,146 @@
+package installer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestInstaller(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Installer Package Suite")
+}
+
+const multiDocYAML = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+  namespace: test-ns
+data:
+  key: one
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: second
+  namespace: test-ns
+data:
+  key: two
+`
+
+// writeTempYAML writes content to a temporary file and returns its path
+func writeTempYAML(content string) string {
+	path := filepath.Join(GinkgoT().TempDir(), "config.yaml")
+	Expect(os.WriteFile(path, []byte(content), 0644)).To(Succeed())
+	return path
+}
+
+var _ = Describe("InstallConfig", func() {
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("should create all documents of a multi-document file", func() {
+		cli := fake.NewClientBuilder().Build()
+		Expect(InstallConfig(ctx, cli, nil, writeTempYAML(multiDocYAML))).To(Succeed())
+
+		cm := &v1.ConfigMap{}
+		Expect(cli.Get(ctx, client.ObjectKey{Namespace: "test-ns", Name: "first"}, cm)).To(Succeed())
+		Expect(cm.Data).To(HaveKeyWithValue("key", "one"))
+		Expect(cli.Get(ctx, client.ObjectKey{Namespace: "test-ns", Name: "second"}, cm)).To(Succeed())
+		Expect(cm.Data).To(HaveKeyWithValue("key", "two"))
+	})
+
+	It("should update resources that already exist", func() {
+		existing := &v1.ConfigMap{}
+		existing.Namespace = "test-ns"
+		existing.Name = "first"
+		existing.Data = map[string]string{"key": "old"}
+		cli := fake.NewClientBuilder().WithObjects(existing).Build()
+
+		results, err := ApplyDocuments(ctx, cli, splitYAMLDocuments(multiDocYAML))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(HaveLen(2))
+		Expect(results[0].Operation).To(Equal(OperationUpdated))
+		Expect(results[1].Operation).To(Equal(OperationCreated))
+
+		cm := &v1.ConfigMap{}
+		Expect(cli.Get(ctx, client.ObjectKey{Namespace: "test-ns", Name: "first"}, cm)).To(Succeed())
+		Expect(cm.Data).To(HaveKeyWithValue("key", "one"))
+	})
+
+	It("should return an error when neither config nor file path is given", func() {
+		cli := fake.NewClientBuilder().Build()
+		err := InstallConfig(ctx, cli, nil, "")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Server-side dry run", func() {
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("should not persist any resource", func() {
+		cli := fake.NewClientBuilder().Build()
+		results, err := ValidateConfig(ctx, cli, nil, writeTempYAML(multiDocYAML))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(HaveLen(2))
+		for _, result := range results {
+			Expect(result.DryRun).To(BeTrue())
+			Expect(result.Err).NotTo(HaveOccurred())
+		}
+
+		cm := &v1.ConfigMap{}
+		err = cli.Get(ctx, client.ObjectKey{Namespace: "test-ns", Name: "first"}, cm)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should report validation errors for every failing document", func() {
+		// Reject every create the way an admission webhook would
+		cli := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				createOpts := &client.CreateOptions{}
+				createOpts.ApplyOptions(opts)
+				Expect(createOpts.DryRun).To(ConsistOf("All"))
+				return apierrors.NewInvalid(schema.GroupKind{Kind: "ConfigMap"}, obj.GetName(), nil)
+			},
+		}).Build()
+
+		results, err := ValidateConfig(ctx, cli, nil, writeTempYAML(multiDocYAML))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("2 document(s)"))
+		Expect(results).To(HaveLen(2))
+		Expect(results[0].Err).To(HaveOccurred())
+		Expect(results[0].Index).To(Equal(1))
+		Expect(results[1].Err).To(HaveOccurred())
+		Expect(results[1].Index).To(Equal(2))
+	})
+
+	It("should stop at the first error without dry run", func() {
+		cli := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				return apierrors.NewInvalid(schema.GroupKind{Kind: "ConfigMap"}, obj.GetName(), nil)
+			},
+		}).Build()
+
+		results, err := ApplyDocuments(ctx, cli, splitYAMLDocuments(multiDocYAML))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("failed to create resource 1"))
+		Expect(results).To(HaveLen(1))
+	})
+})
@@ -0,0 +1,49 @@
+package installer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// KustomizeSource returns a Source that builds a kustomization directory in-process
+// It is the equivalent of `kustomize build <path>`
+func KustomizeSource(path string) Source {
+	return kustomizeSource{path: path}
+}
+
+type kustomizeSource struct {
+	path string
+}
+
+func (s kustomizeSource) Documents() ([]string, error) {
+	kustomizer := krusty.MakeKustomizer(krusty.MakeDefaultOptions())
+	resources, err := kustomizer.Run(filesys.MakeFsOnDisk(), s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build kustomization: %w", err)
+	}
+
+	data, err := resources.AsYaml()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert kustomize output to YAML: %w", err)
+	}
+	return splitYAMLDocuments(string(data)), nil
+}
+
+func (s kustomizeSource) String() string {
+	return "kustomization " + s.path
+}
+
+// isKustomization returns true if dir contains a kustomization file
+func isKustomization(dir string) bool {
+	for _, name := range konfig.RecognizedKustomizationFileNames() {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package installer
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// clusterScopedKinds are well-known cluster-scoped kinds
+// They are used when the REST mapper does not know a kind, e.g. with the fake client
+var clusterScopedKinds = map[schema.GroupKind]struct{}{
+	{Group: "", Kind: "Namespace"}:                                                  {},
+	{Group: "", Kind: "Node"}:                                                       {},
+	{Group: "", Kind: "PersistentVolume"}:                                           {},
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}:                       {},
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"}:                {},
+	{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}:               {},
+	{Group: "storage.k8s.io", Kind: "StorageClass"}:                                 {},
+	{Group: "scheduling.k8s.io", Kind: "PriorityClass"}:                             {},
+	{Group: "networking.k8s.io", Kind: "IngressClass"}:                              {},
+	{Group: "apiregistration.k8s.io", Kind: "APIService"}:                           {},
+	{Group: "admissionregistration.k8s.io", Kind: "MutatingWebhookConfiguration"}:   {},
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration"}: {},
+	{Group: "project.openshift.io", Kind: "Project"}:                                {},
+	{Group: "config.openshift.io", Kind: "ClusterVersion"}:                          {},
+}
+
+// isNamespaced returns true if gvk is a namespaced kind
+// The REST mapper is asked first, unknown kinds fall back to clusterScopedKinds
+func isNamespaced(mapper meta.RESTMapper, gvk schema.GroupVersionKind) (bool, error) {
+	if mapper != nil {
+		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err == nil {
+			return mapping.Scope.Name() == meta.RESTScopeNameNamespace, nil
+		}
+		if !meta.IsNoMatchError(err) {
+			return false, fmt.Errorf("failed to get REST mapping for %s: %w", gvk, err)
+		}
+	}
+
+	_, clusterScoped := clusterScopedKinds[gvk.GroupKind()]
+	return !clusterScoped, nil
+}
+
+// resolveNamespaces checks and rewrites metadata.namespace of every document
+//   - namespaced objects get the target namespace if they have none
+//   - with a forced namespace the target always wins, otherwise a different namespace is an error
+//   - namespaced objects without any namespace are an error, they would silently land in "default"
+//   - cluster-scoped objects are refused unless allowed, their namespace is cleared
+//
+// All problems are reported in a single error
+func resolveNamespaces(mapper meta.RESTMapper, documents []document, options *Options) error {
+	var problems []string
+
+	for _, doc := range documents {
+		obj := doc.obj
+		ref := fmt.Sprintf("document %d (%s/%s)", doc.index, obj.GetKind(), obj.GetName())
+
+		namespaced, err := isNamespaced(mapper, obj.GroupVersionKind())
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", ref, err))
+			continue
+		}
+
+		if !namespaced {
+			if !options.AllowClusterScoped {
+				problems = append(problems, fmt.Sprintf("%s: cluster-scoped resources are not allowed", ref))
+				continue
+			}
+			obj.SetNamespace("")
+			continue
+		}
+
+		switch {
+		case options.Namespace == "" && obj.GetNamespace() == "":
+			problems = append(problems, fmt.Sprintf("%s: namespace is not set", ref))
+		case options.Namespace == "":
+			// Nothing to enforce
+		case obj.GetNamespace() == "" || options.ForceNamespace:
+			obj.SetNamespace(options.Namespace)
+		case obj.GetNamespace() != options.Namespace:
+			problems = append(problems, fmt.Sprintf("%s: namespace %q does not match target namespace %q",
+				ref, obj.GetNamespace(), options.Namespace))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid resource scope:\n%s", strings.Join(problems, "\n"))
+	}
+	return nil
+}
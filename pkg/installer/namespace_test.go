@@ -0,0 +1,92 @@
+package installer
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const noNamespaceYAML = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: forgotten
+`
+
+const namespaceYAML = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: extra
+  namespace: should-be-cleared
+`
+
+var _ = Describe("Namespace handling", func() {
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("should install objects without namespace into the target namespace", func() {
+		cli := fake.NewClientBuilder().Build()
+		_, err := ApplyDocuments(ctx, cli, []string{noNamespaceYAML}, WithNamespace("target"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cli.Get(ctx, client.ObjectKey{Namespace: "target", Name: "forgotten"}, &v1.ConfigMap{})).To(Succeed())
+	})
+
+	It("should refuse namespaced objects without any namespace", func() {
+		cli := fake.NewClientBuilder().Build()
+		_, err := ApplyDocuments(ctx, cli, []string{noNamespaceYAML})
+		Expect(err).To(MatchError(ContainSubstring("document 1 (ConfigMap/forgotten): namespace is not set")))
+	})
+
+	It("should refuse objects in a different namespace", func() {
+		cli := fake.NewClientBuilder().Build()
+		_, err := ApplyDocuments(ctx, cli, []string{configMapYAML}, WithNamespace("target"))
+		Expect(err).To(MatchError(ContainSubstring(`namespace "test-ns" does not match target namespace "target"`)))
+	})
+
+	It("should rewrite the namespace when overriding", func() {
+		cli := fake.NewClientBuilder().Build()
+		results, err := ApplyDocuments(ctx, cli, []string{configMapYAML}, WithNamespaceOverride("target"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results[0].Namespace).To(Equal("target"))
+		Expect(cli.Get(ctx, client.ObjectKey{Namespace: "target", Name: "first"}, &v1.ConfigMap{})).To(Succeed())
+	})
+
+	It("should refuse cluster-scoped objects unless allowed", func() {
+		cli := fake.NewClientBuilder().Build()
+		_, err := ApplyDocuments(ctx, cli, []string{namespaceYAML}, WithNamespace("target"))
+		Expect(err).To(MatchError(ContainSubstring("cluster-scoped resources are not allowed")))
+
+		results, err := ApplyDocuments(ctx, cli, []string{namespaceYAML}, WithNamespace("target"), WithClusterScoped())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results[0].Namespace).To(BeEmpty())
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "extra"}, &v1.Namespace{})).To(Succeed())
+	})
+
+	It("should use the REST mapper to detect the scope of custom resources", func() {
+		gvk := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+		mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{gvk.GroupVersion()})
+		mapper.Add(gvk, meta.RESTScopeRoot)
+		cli := fake.NewClientBuilder().WithRESTMapper(mapper).Build()
+
+		widget := "apiVersion: example.com/v1\nkind: Widget\nmetadata:\n  name: w\n"
+		_, err := ApplyDocuments(ctx, cli, []string{widget}, WithNamespace("target"))
+		Expect(err).To(MatchError(ContainSubstring("document 1 (Widget/w): cluster-scoped resources are not allowed")))
+	})
+
+	It("should report all scope problems at once", func() {
+		cli := fake.NewClientBuilder().Build()
+		_, err := ApplyDocuments(ctx, cli, []string{noNamespaceYAML, namespaceYAML})
+		Expect(err).To(MatchError(And(
+			ContainSubstring("document 1"),
+			ContainSubstring("document 2"),
+		)))
+	})
+})
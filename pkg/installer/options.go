@@ -0,0 +1,86 @@
+package installer
+
+// Options controls how documents are applied to the cluster
+type Options struct {
+	// DryRun sends every request with dryRun=All so the API server validates
+	// the documents (OpenAPI schema, admission webhooks) without persisting them
+	DryRun bool
+	// IgnoreDefaults makes Diff ignore status, server metadata (managedFields,
+	// resourceVersion, ...) and any field the operator added to the live object
+	IgnoreDefaults bool
+	// Ownership, when set, is stamped on every installed resource
+	Ownership *Ownership
+	// Helm configures rendering when the installed path is a Helm chart
+	Helm HelmOptions
+	// Namespace is the target namespace for namespaced objects
+	// Objects without a namespace get it, objects with a different namespace are refused
+	Namespace string
+	// ForceNamespace overwrites the namespace of all namespaced objects with Namespace
+	ForceNamespace bool
+	// AllowClusterScoped allows installing cluster-scoped objects such as Namespaces or CRDs
+	AllowClusterScoped bool
+}
+
+// Option configures Options
+type Option func(*Options)
+
+// WithDryRun enables server-side dry run for all documents
+func WithDryRun() Option {
+	return func(o *Options) {
+		o.DryRun = true
+	}
+}
+
+// WithIgnoreDefaults makes Diff compare only the fields present in the rendered documents
+func WithIgnoreDefaults() Option {
+	return func(o *Options) {
+		o.IgnoreDefaults = true
+	}
+}
+
+// WithOwnership stamps run tracking labels and annotations on every installed resource
+func WithOwnership(ownership Ownership) Option {
+	return func(o *Options) {
+		o.Ownership = &ownership
+	}
+}
+
+// WithHelm sets the release name, namespace and values files used to render Helm charts
+func WithHelm(helm HelmOptions) Option {
+	return func(o *Options) {
+		o.Helm = helm
+	}
+}
+
+// WithNamespace sets the target namespace for namespaced objects
+// Objects without a namespace are installed into it, objects in a different namespace are refused
+func WithNamespace(namespace string) Option {
+	return func(o *Options) {
+		o.Namespace = namespace
+		o.ForceNamespace = false
+	}
+}
+
+// WithNamespaceOverride installs all namespaced objects into namespace, whatever their metadata says
+func WithNamespaceOverride(namespace string) Option {
+	return func(o *Options) {
+		o.Namespace = namespace
+		o.ForceNamespace = true
+	}
+}
+
+// WithClusterScoped allows installing cluster-scoped objects
+func WithClusterScoped() Option {
+	return func(o *Options) {
+		o.AllowClusterScoped = true
+	}
+}
+
+// newOptions returns Options with all given options applied
+func newOptions(opts ...Option) *Options {
+	options := &Options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
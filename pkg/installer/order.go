@@ -0,0 +1,67 @@
+package installer
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// installOrder lists kinds that other resources depend on, in the order they are installed
+// Kinds not listed here (e.g., Securesign and other custom resources) are installed afterwards
+// The list follows the Helm install order
+var installOrder = []string{
+	"Namespace",
+	"NetworkPolicy",
+	"ResourceQuota",
+	"LimitRange",
+	"PodDisruptionBudget",
+	"ServiceAccount",
+	"Secret",
+	"ConfigMap",
+	"StorageClass",
+	"PersistentVolume",
+	"PersistentVolumeClaim",
+	"CustomResourceDefinition",
+	"ClusterRole",
+	"ClusterRoleBinding",
+	"Role",
+	"RoleBinding",
+	"Service",
+	"DaemonSet",
+	"Pod",
+	"ReplicaSet",
+	"Deployment",
+	"HorizontalPodAutoscaler",
+	"StatefulSet",
+	"Job",
+	"CronJob",
+	"IngressClass",
+	"Ingress",
+	"Route",
+	"APIService",
+}
+
+// document is a parsed YAML document with its 1-based position in the source
+type document struct {
+	index int
+	obj   *unstructured.Unstructured
+}
+
+// sortDocuments orders documents by installOrder
+// The sort is stable so documents of the same kind keep their source order
+func sortDocuments(documents []document) {
+	priority := make(map[string]int, len(installOrder))
+	for i, kind := range installOrder {
+		priority[kind] = i
+	}
+	rank := func(kind string) int {
+		if p, ok := priority[kind]; ok {
+			return p
+		}
+		return len(installOrder)
+	}
+
+	sort.SliceStable(documents, func(i, j int) bool {
+		return rank(documents[i].obj.GetKind()) < rank(documents[j].obj.GetKind())
+	})
+}
@@ -0,0 +1,97 @@
+package installer
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Labels and annotations stamped on resources created by this suite
+const (
+	// LabelManagedBy marks every resource created by the suite, used as selector for garbage collection
+	LabelManagedBy = "app.kubernetes.io/managed-by"
+	// ManagedByValue is the value of LabelManagedBy
+	ManagedByValue = "config-examples"
+	// LabelRunID identifies the test run that created the resource
+	LabelRunID = "config-examples/run-id"
+	// LabelScenario is the scenario name (e.g., "default")
+	LabelScenario = "config-examples/scenario"
+	// LabelVariant is the scenario variant name (e.g., "base")
+	LabelVariant = "config-examples/variant"
+	// AnnotationCreatedAt is the RFC3339 creation timestamp, used to compute the age of a run
+	AnnotationCreatedAt = "config-examples/created-at"
+)
+
+// Ownership identifies the test run that created a resource
+// Empty fields are not stamped
+type Ownership struct {
+	RunID     string
+	Scenario  string
+	Variant   string
+	CreatedAt time.Time
+	// Labels and Annotations are additional metadata stamped on every resource
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// NewRunID generates a unique, label-safe run ID (e.g., "20250101-120000-1a2b3c")
+func NewRunID() string {
+	suffix := make([]byte, 3)
+	_, _ = rand.Read(suffix) // crypto/rand.Read never returns an error
+	return time.Now().UTC().Format("20060102-150405") + "-" + hex.EncodeToString(suffix)
+}
+
+// Apply stamps the ownership labels and annotations on an object
+// Existing labels and annotations are kept, ownership values take precedence
+func (o Ownership) Apply(obj metav1.Object) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	labels[LabelManagedBy] = ManagedByValue
+	setIfNotEmpty(labels, LabelRunID, o.RunID)
+	setIfNotEmpty(labels, LabelScenario, o.Scenario)
+	setIfNotEmpty(labels, LabelVariant, o.Variant)
+	for key, value := range o.Labels {
+		labels[key] = value
+	}
+
+	createdAt := o.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	annotations[AnnotationCreatedAt] = createdAt.UTC().Format(time.RFC3339)
+	for key, value := range o.Annotations {
+		annotations[key] = value
+	}
+
+	obj.SetLabels(labels)
+	obj.SetAnnotations(annotations)
+}
+
+// CreatedAt returns the creation timestamp stamped by Ownership.Apply
+// The second return value is false if the annotation is missing or invalid
+func CreatedAt(obj metav1.Object) (time.Time, bool) {
+	value, ok := obj.GetAnnotations()[AnnotationCreatedAt]
+	if !ok {
+		return time.Time{}, false
+	}
+	createdAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return createdAt, true
+}
+
+func setIfNotEmpty(m map[string]string, key, value string) {
+	if value != "" {
+		m[key] = value
+	}
+}
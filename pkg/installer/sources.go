@@ -0,0 +1,155 @@
+package installer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/petrpinkas/config-examples/pkg/config"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Source provides the YAML documents to install
+type Source interface {
+	// Documents returns the YAML documents in source order
+	Documents() ([]string, error)
+	// String describes the source for logs and error messages
+	String() string
+}
+
+// FileSource returns a Source reading a single, possibly multi-document, YAML file
+func FileSource(path string) Source {
+	return fileSource{path: path}
+}
+
+// DirectorySource returns a Source reading all *.yaml and *.yml files in a directory tree
+// Files are read in lexical order
+func DirectorySource(path string) Source {
+	return directorySource{path: path}
+}
+
+// ConfigSource returns a Source for an in-memory config
+// Only the single document held by the config is installed
+func ConfigSource(cfg *config.Config) Source {
+	return configSource{cfg: cfg}
+}
+
+// SourceForPath detects the kind of source at path
+//   - a file is read as multi-document YAML
+//   - a directory with a kustomization file is built with kustomize
+//   - a directory with a Chart.yaml is rendered as a Helm chart using helm options
+//   - any other directory is read as a directory of manifests
+func SourceForPath(path string, helm HelmOptions) (Source, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	if !info.IsDir() {
+		return FileSource(path), nil
+	}
+
+	if isKustomization(path) {
+		return KustomizeSource(path), nil
+	}
+	if _, err := os.Stat(filepath.Join(path, "Chart.yaml")); err == nil {
+		return HelmSource(path, helm), nil
+	}
+	return DirectorySource(path), nil
+}
+
+// Objects returns the parsed objects of a source in source order, empty documents are skipped
+func Objects(src Source) ([]*unstructured.Unstructured, error) {
+	documents, err := src.Documents()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", src, err)
+	}
+	parsed, err := parseDocuments(documents)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]*unstructured.Unstructured, len(parsed))
+	for i, doc := range parsed {
+		objects[i] = doc.obj
+	}
+	return objects, nil
+}
+
+// sourceFor returns the Source used by InstallConfig, ValidateConfig and DiffConfig
+func sourceFor(cfg *config.Config, path string, options *Options) (Source, error) {
+	if path != "" {
+		helm := options.Helm
+		if helm.Namespace == "" {
+			helm.Namespace = options.Namespace
+		}
+		return SourceForPath(path, helm)
+	}
+	if cfg == nil {
+		return nil, fmt.Errorf("either a config or a file path is required")
+	}
+	return ConfigSource(cfg), nil
+}
+
+type fileSource struct {
+	path string
+}
+
+func (s fileSource) Documents() ([]string, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	// Split multi-document YAML into individual documents
+	return splitYAMLDocuments(string(data)), nil
+}
+
+func (s fileSource) String() string {
+	return "file " + s.path
+}
+
+type directorySource struct {
+	path string
+}
+
+func (s directorySource) Documents() ([]string, error) {
+	files, err := config.FindConfigFiles(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list manifests: %w", err)
+	}
+
+	var documents []string
+	for _, file := range files {
+		docs, err := FileSource(file).Documents()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		for _, doc := range docs {
+			if strings.TrimSpace(doc) != "" {
+				documents = append(documents, doc)
+			}
+		}
+	}
+	return documents, nil
+}
+
+func (s directorySource) String() string {
+	return "directory " + s.path
+}
+
+type configSource struct {
+	cfg *config.Config
+}
+
+func (s configSource) Documents() ([]string, error) {
+	// ToYAML() only contains the first document
+	data, err := s.cfg.ToYAML()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert config to YAML: %w", err)
+	}
+	return splitYAMLDocuments(string(data)), nil
+}
+
+func (s configSource) String() string {
+	return fmt.Sprintf("config %s/%s", s.cfg.GetKind(), s.cfg.GetName())
+}
@@ -0,0 +1,164 @@
+package installer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// writeFiles creates files relative to dir, creating parent directories as needed
+func writeFiles(dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(content), 0644)).To(Succeed())
+	}
+}
+
+const chartYAML = `apiVersion: v2
+name: demo
+version: 0.1.0
+`
+
+const chartTemplate = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}-config
+  namespace: {{ .Release.Namespace }}
+data:
+  greeting: {{ .Values.greeting }}
+  replicas: "{{ .Values.replicas }}"
+`
+
+var _ = Describe("Sources", func() {
+	var dir string
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+	})
+
+	It("should read all manifests of a directory tree in lexical order", func() {
+		writeFiles(dir, map[string]string{
+			"b.yaml":        "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: b\n",
+			"a.yml":         "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a2\n",
+			"nested/c.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: c\n",
+			"README.md":     "not a manifest",
+		})
+
+		src, err := SourceForPath(dir, HelmOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(src.String()).To(HavePrefix("directory"))
+
+		documents, err := src.Documents()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(documents).To(HaveLen(4))
+		Expect(documents[0]).To(ContainSubstring("name: a"))
+		Expect(documents[1]).To(ContainSubstring("name: a2"))
+		Expect(documents[2]).To(ContainSubstring("name: b"))
+		Expect(documents[3]).To(ContainSubstring("name: c"))
+	})
+
+	It("should parse the objects of a source", func() {
+		objects, err := Objects(FileSource(writeTempYAML(multiDocYAML + "---\n# only a comment\n")))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(objects).To(HaveLen(2))
+		Expect(objects[0].GetName()).To(Equal("first"))
+		Expect(objects[1].GetKind()).To(Equal("ConfigMap"))
+	})
+
+	It("should build a kustomization in-process", func() {
+		writeFiles(dir, map[string]string{
+			"kustomization.yaml": "namePrefix: dev-\nnamespace: kustomized\nresources:\n  - cm.yaml\n",
+			"cm.yaml":            "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\ndata:\n  key: value\n",
+		})
+
+		src, err := SourceForPath(dir, HelmOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(src.String()).To(HavePrefix("kustomization"))
+
+		documents, err := src.Documents()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(documents).To(HaveLen(1))
+		Expect(documents[0]).To(ContainSubstring("name: dev-settings"))
+		Expect(documents[0]).To(ContainSubstring("namespace: kustomized"))
+	})
+
+	It("should render a local Helm chart with values files", func() {
+		writeFiles(dir, map[string]string{
+			"chart/Chart.yaml":          chartYAML,
+			"chart/values.yaml":         "greeting: hello\nreplicas: 1\n",
+			"chart/templates/cm.yaml":   chartTemplate,
+			"chart/templates/_help.tpl": `{{- define "demo.name" -}}demo{{- end -}}`,
+			"chart/templates/NOTES.txt": "Installed {{ .Release.Name }}",
+			"chart/crds/crd.yaml":       "apiVersion: apiextensions.k8s.io/v1\nkind: CustomResourceDefinition\nmetadata:\n  name: demos.example.com\n",
+			"values-override.yaml":      "greeting: overridden\n",
+			"values-override-more.yaml": "replicas: 3\n",
+		})
+
+		src, err := SourceForPath(filepath.Join(dir, "chart"), HelmOptions{
+			ReleaseName: "rel",
+			Namespace:   "helm-ns",
+			ValuesFiles: []string{
+				filepath.Join(dir, "values-override.yaml"),
+				filepath.Join(dir, "values-override-more.yaml"),
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(src.String()).To(HavePrefix("chart"))
+
+		documents, err := src.Documents()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(documents).To(HaveLen(2))
+		Expect(documents[0]).To(ContainSubstring("kind: CustomResourceDefinition"))
+		Expect(documents[1]).To(ContainSubstring("name: rel-config"))
+		Expect(documents[1]).To(ContainSubstring("namespace: helm-ns"))
+		Expect(documents[1]).To(ContainSubstring("greeting: overridden"))
+		Expect(documents[1]).To(ContainSubstring(`replicas: "3"`))
+	})
+
+	It("should install a chart through InstallConfig", func() {
+		writeFiles(dir, map[string]string{
+			"Chart.yaml":        chartYAML,
+			"values.yaml":       "greeting: hello\nreplicas: 1\n",
+			"templates/cm.yaml": chartTemplate,
+		})
+
+		ctx := context.Background()
+		cli := fake.NewClientBuilder().Build()
+		Expect(InstallConfig(ctx, cli, nil, dir, WithHelm(HelmOptions{Namespace: "helm-ns"}))).To(Succeed())
+
+		cm := &v1.ConfigMap{}
+		Expect(cli.Get(ctx, client.ObjectKey{Namespace: "helm-ns", Name: "demo-config"}, cm)).To(Succeed())
+		Expect(cm.Data).To(HaveKeyWithValue("greeting", "hello"))
+	})
+})
+
+var _ = Describe("Install order", func() {
+	It("should install dependencies before custom resources and keep source order otherwise", func() {
+		documents := []string{
+			"apiVersion: rhtas.redhat.com/v1alpha1\nkind: Trillian\nmetadata:\n  name: trillian\n",
+			"apiVersion: rhtas.redhat.com/v1alpha1\nkind: Rekor\nmetadata:\n  name: rekor\n",
+			"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n",
+			"apiVersion: v1\nkind: Namespace\nmetadata:\n  name: ns\n",
+		}
+
+		parsed, err := parseDocuments(documents)
+		Expect(err).NotTo(HaveOccurred())
+		sortDocuments(parsed)
+
+		var kinds []string
+		var indexes []int
+		for _, doc := range parsed {
+			kinds = append(kinds, doc.obj.GetKind())
+			indexes = append(indexes, doc.index)
+		}
+		Expect(kinds).To(Equal([]string{"Namespace", "ConfigMap", "Trillian", "Rekor"}))
+		Expect(indexes).To(Equal([]int{4, 3, 1, 2}))
+	})
+})
@@ -1,34 +1,181 @@
 package kubernetes
 
 import (
+	"fmt"
 	"sync"
 
+	"github.com/petrpinkas/config-examples/pkg/apis/rhtas/v1alpha1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 )
 
 var (
-	k8sClient client.Client
-	once      sync.Once
+	defaultMu      sync.Mutex
+	defaultFactory *Factory
 )
 
-// GetClient returns a singleton Kubernetes client
-func GetClient() (client.Client, error) {
+// NewScheme returns a scheme with the client-go and RHTAS (v1alpha1) types registered
+func NewScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+	return scheme
+}
+
+// Factory creates the clients for one cluster
+// Client and Clientset keep the client of the first successful call, a failed call is retried by the next one
+type Factory struct {
+	options *Options
+
+	mu        sync.Mutex
+	client    client.WithWatch
+	clientset kubernetes.Interface
+}
+
+// NewFactory returns a factory for the cluster configured by opts, independent of all other factories
+func NewFactory(opts ...Option) *Factory {
+	return &Factory{options: newOptions(opts...)}
+}
+
+// RESTConfig returns a new config for the kubeconfig, context and request settings of the factory
+func (f *Factory) RESTConfig() (*rest.Config, error) {
+	o := f.options
+	var cfg *rest.Config
 	var err error
-	once.Do(func() {
-		scheme := runtime.NewScheme()
-		utilruntime.Must(clientgoscheme.AddToScheme(scheme))
-
-		cfg, cfgErr := config.GetConfig()
-		if cfgErr != nil {
-			err = cfgErr
-			return
+	if o.Kubeconfig == "" && o.Context == "" {
+		cfg, err = config.GetConfig()
+	} else {
+		rules := clientcmd.NewDefaultClientConfigLoadingRules()
+		if o.Kubeconfig != "" {
+			rules.ExplicitPath = o.Kubeconfig
 		}
+		overrides := &clientcmd.ConfigOverrides{CurrentContext: o.Context}
+		cfg, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides).ClientConfig()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	if o.Impersonate.UserName != "" {
+		cfg.Impersonate = o.Impersonate
+	}
+	if o.QPS > 0 {
+		cfg.QPS = o.QPS
+	}
+	if o.Burst > 0 {
+		cfg.Burst = o.Burst
+	}
+	if o.Timeout > 0 {
+		cfg.Timeout = o.Timeout
+	}
+	if o.UserAgent != "" {
+		cfg.UserAgent = o.UserAgent
+	}
+	return cfg, nil
+}
+
+// Client returns the controller-runtime client of the factory, it supports watches
+func (f *Factory) Client() (client.WithWatch, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.client == nil {
+		cli, err := f.NewClient()
+		if err != nil {
+			return nil, err
+		}
+		f.client = cli
+	}
+	return f.client, nil
+}
+
+// Clientset returns the client-go clientset of the factory
+// It is needed for subresources the controller-runtime client does not support, such as pod logs
+func (f *Factory) Clientset() (kubernetes.Interface, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.clientset == nil {
+		clientset, err := f.NewClientset()
+		if err != nil {
+			return nil, err
+		}
+		f.clientset = clientset
+	}
+	return f.clientset, nil
+}
+
+// NewClient returns a new controller-runtime client, or the injected one (WithClient)
+func (f *Factory) NewClient() (client.WithWatch, error) {
+	if f.options.Client != nil {
+		return f.options.Client, nil
+	}
+	cfg, err := f.RESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	scheme := f.options.Scheme
+	if scheme == nil {
+		scheme = NewScheme()
+	}
+	cli, err := client.NewWithWatch(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+	return cli, nil
+}
+
+// NewClientset returns a new client-go clientset, or the injected one (WithClientset)
+func (f *Factory) NewClientset() (kubernetes.Interface, error) {
+	if f.options.Clientset != nil {
+		return f.options.Clientset, nil
+	}
+	cfg, err := f.RESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create clientset: %w", err)
+	}
+	return clientset, nil
+}
+
+// DefaultFactory returns the factory used by GetClient and GetClientset
+// Unless set with SetDefaultFactory, it is created on first use for the cluster of the selected profile (api.SelectedProfile)
+func DefaultFactory() *Factory {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if defaultFactory == nil {
+		defaultFactory = NewFactory(ProfileOptions()...)
+	}
+	return defaultFactory
+}
+
+// SetDefaultFactory replaces the factory used by GetClient and GetClientset, e.g. with one returning fakes
+// With nil the next call creates a new factory for the selected profile
+func SetDefaultFactory(f *Factory) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultFactory = f
+}
+
+// GetClient returns the client of the default factory
+// The client supports watches, it can be type asserted to client.WithWatch
+func GetClient() (client.Client, error) {
+	cli, err := DefaultFactory().Client()
+	if err != nil {
+		return nil, err
+	}
+	return cli, nil
+}
 
-		k8sClient, err = client.New(cfg, client.Options{Scheme: scheme})
-	})
-	return k8sClient, err
+// GetClientset returns the clientset of the default factory
+// It is needed for subresources the controller-runtime client does not support, such as pod logs
+func GetClientset() (kubernetes.Interface, error) {
+	return DefaultFactory().Clientset()
 }
@@ -0,0 +1,134 @@
+package kubernetes
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/petrpinkas/config-examples/pkg/apis/rhtas/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestKubernetes(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Kubernetes Package Suite")
+}
+
+const kubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: qe-a
+  cluster:
+    server: https://api.qe-a.example.com:6443
+- name: qe-b
+  cluster:
+    server: https://api.qe-b.example.com:6443
+users:
+- name: admin
+  user:
+    token: sha256~admin
+contexts:
+- name: admin@qe-a
+  context:
+    cluster: qe-a
+    user: admin
+- name: admin@qe-b
+  context:
+    cluster: qe-b
+    user: admin
+current-context: admin@qe-a
+`
+
+var _ = Describe("Factory", func() {
+	var file string
+
+	BeforeEach(func() {
+		file = filepath.Join(GinkgoT().TempDir(), "kubeconfig")
+	})
+
+	writeKubeconfig := func() {
+		Expect(os.WriteFile(file, []byte(kubeconfig), 0o600)).To(Succeed())
+	}
+
+	It("should apply the kubeconfig, context and request settings", func() {
+		writeKubeconfig()
+		f := NewFactory(
+			WithKubeconfig(file),
+			WithContext("admin@qe-b"),
+			WithImpersonation("system:serviceaccount:rhtas:tester", "system:authenticated"),
+			WithRateLimit(50, 100),
+			WithTimeout(30*time.Second),
+			WithUserAgent("rhtas-config-tests"),
+		)
+		cfg, err := f.RESTConfig()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Host).To(Equal("https://api.qe-b.example.com:6443"))
+		Expect(cfg.Impersonate.UserName).To(Equal("system:serviceaccount:rhtas:tester"))
+		Expect(cfg.Impersonate.Groups).To(ConsistOf("system:authenticated"))
+		Expect(cfg.QPS).To(BeNumerically("==", 50))
+		Expect(cfg.Burst).To(Equal(100))
+		Expect(cfg.Timeout).To(Equal(30 * time.Second))
+		Expect(cfg.UserAgent).To(Equal("rhtas-config-tests"))
+
+		cfg, err = NewFactory(WithKubeconfig(file)).RESTConfig()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Host).To(Equal("https://api.qe-a.example.com:6443"))
+		Expect(cfg.Impersonate.UserName).To(BeEmpty())
+	})
+
+	It("should create the clients again after a failure", func() {
+		f := NewFactory(WithKubeconfig(file))
+		_, err := f.Client()
+		Expect(err).To(MatchError(ContainSubstring("failed to load kubeconfig")))
+		_, err = f.Clientset()
+		Expect(err).To(HaveOccurred())
+
+		writeKubeconfig()
+		cli, err := f.Client()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Client()).To(BeIdenticalTo(cli))
+		clientset, err := f.Clientset()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Clientset()).To(BeIdenticalTo(clientset))
+
+		other, err := NewFactory(WithKubeconfig(file)).Client()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(other).NotTo(BeIdenticalTo(cli))
+	})
+
+	It("should return injected clients from the default factory", func() {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "rhtas", Name: "fulcio"}}
+		SetDefaultFactory(NewFactory(
+			WithClient(fake.NewClientBuilder().WithObjects(pod).Build()),
+			WithClientset(clientsetfake.NewSimpleClientset(pod)),
+		))
+		DeferCleanup(func() { SetDefaultFactory(nil) })
+
+		cli, err := GetClient()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cli.Get(context.Background(), client.ObjectKeyFromObject(pod), &corev1.Pod{})).To(Succeed())
+
+		clientset, err := GetClientset()
+		Expect(err).NotTo(HaveOccurred())
+		_, err = clientset.CoreV1().Pods("rhtas").Get(context.Background(), "fulcio", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should register the RHTAS types in the scheme", func() {
+		scheme := NewScheme()
+		Expect(scheme.Recognizes(v1alpha1.SecuresignGVK)).To(BeTrue())
+		Expect(scheme.Recognizes(v1alpha1.TimestampAuthorityGVK)).To(BeTrue())
+
+		securesign := &v1alpha1.Securesign{ObjectMeta: metav1.ObjectMeta{Namespace: "rhtas", Name: "securesign-sample"}}
+		cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(securesign).Build()
+		Expect(cli.Get(context.Background(), client.ObjectKeyFromObject(securesign), &v1alpha1.Securesign{})).To(Succeed())
+	})
+})
@@ -0,0 +1,119 @@
+package kubernetes
+
+import (
+	"time"
+
+	"github.com/petrpinkas/config-examples/pkg/api"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Options controls how a Factory connects to the cluster
+type Options struct {
+	// Kubeconfig is the kubeconfig file, the usual lookup applies if empty
+	// (--kubeconfig, KUBECONFIG, in-cluster config, ~/.kube/config)
+	Kubeconfig string
+	// Context is the kubeconfig context, the current context if empty
+	Context string
+	// Impersonate sends requests as another user, e.g. to check RBAC of a service account
+	Impersonate rest.ImpersonationConfig
+	// QPS and Burst limit the requests to the API server, the client-go defaults apply if zero
+	QPS   float32
+	Burst int
+	// Timeout limits a single request, no limit if zero
+	Timeout time.Duration
+	// UserAgent identifies the suite in audit logs, the client-go default if empty
+	UserAgent string
+	// Scheme maps Go types to GVKs for the controller-runtime client, NewScheme() if nil
+	Scheme *runtime.Scheme
+	// Client and Clientset are returned instead of connecting to a cluster, e.g. fakes in unit tests
+	Client    client.WithWatch
+	Clientset kubernetes.Interface
+}
+
+// Option configures Options
+type Option func(*Options)
+
+// WithKubeconfig connects to the cluster of a kubeconfig file
+func WithKubeconfig(path string) Option {
+	return func(o *Options) {
+		o.Kubeconfig = path
+	}
+}
+
+// WithContext connects to the cluster of a kubeconfig context
+func WithContext(context string) Option {
+	return func(o *Options) {
+		o.Context = context
+	}
+}
+
+// WithImpersonation sends all requests as user with the given groups
+func WithImpersonation(user string, groups ...string) Option {
+	return func(o *Options) {
+		o.Impersonate = rest.ImpersonationConfig{UserName: user, Groups: groups}
+	}
+}
+
+// WithRateLimit sets the requests per second and the burst allowed above it
+func WithRateLimit(qps float32, burst int) Option {
+	return func(o *Options) {
+		o.QPS = qps
+		o.Burst = burst
+	}
+}
+
+// WithTimeout limits the duration of a single request
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.Timeout = timeout
+	}
+}
+
+// WithUserAgent sets the user agent of all requests
+func WithUserAgent(userAgent string) Option {
+	return func(o *Options) {
+		o.UserAgent = userAgent
+	}
+}
+
+// WithScheme sets the scheme of the controller-runtime client
+func WithScheme(scheme *runtime.Scheme) Option {
+	return func(o *Options) {
+		o.Scheme = scheme
+	}
+}
+
+// WithClient makes the factory return cli instead of connecting, e.g. a fake.NewClientBuilder() client
+func WithClient(cli client.WithWatch) Option {
+	return func(o *Options) {
+		o.Client = cli
+	}
+}
+
+// WithClientset makes the factory return clientset instead of connecting, e.g. a fake.NewSimpleClientset()
+func WithClientset(clientset kubernetes.Interface) Option {
+	return func(o *Options) {
+		o.Clientset = clientset
+	}
+}
+
+// ProfileOptions returns the kubeconfig and context of the selected profile (api.SelectedProfile), nil if none is selected
+func ProfileOptions() []Option {
+	profile := api.SelectedProfile()
+	if profile == nil {
+		return nil
+	}
+	return []Option{WithKubeconfig(profile.Kubeconfig), WithContext(profile.Context)}
+}
+
+// newOptions returns Options with all given options applied
+func newOptions(opts ...Option) *Options {
+	o := &Options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
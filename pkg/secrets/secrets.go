@@ -0,0 +1,226 @@
+// Package secrets resolves secret references such as secret://env/OIDC_PASSWORD in configuration values
+// and masks the resolved values in output
+//
+// A reference has the form secret://<source>/<path>, the built-in sources are
+//   - file: secret://file/path/to/file relative to the working directory, secret://file//abs/path for absolute paths
+//   - env: secret://env/VAR
+//   - k8s: secret://k8s/<namespace>/<name>/<key>, registered with Register("k8s", KubernetesSource(...))
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// Scheme is the prefix of secret references
+const Scheme = "secret://"
+
+// Masked replaces resolved values in masked output
+const Masked = "*****"
+
+// minMaskedLength is the length below which resolved values are not masked, masking "1" or "on" would garble the output
+const minMaskedLength = 4
+
+// ErrUnknownSource means no source is registered for the reference
+var ErrUnknownSource = errors.New("unknown secret source")
+
+// Source resolves the path of a reference, e.g. "ns/name/key" for secret://k8s/ns/name/key
+type Source interface {
+	Resolve(ctx context.Context, path string) (string, error)
+}
+
+// SourceFunc is a function used as Source
+type SourceFunc func(ctx context.Context, path string) (string, error)
+
+// Resolve calls f
+func (f SourceFunc) Resolve(ctx context.Context, path string) (string, error) {
+	return f(ctx, path)
+}
+
+var (
+	mu      sync.RWMutex
+	sources = map[string]Source{
+		"file": FileSource(),
+		"env":  EnvSource(),
+	}
+	// resolved are the values to mask, longest first so that a value containing another is masked as a whole
+	resolved []string
+)
+
+// Register adds or replaces the source for references secret://<name>/...
+func Register(name string, source Source) {
+	mu.Lock()
+	defer mu.Unlock()
+	sources[name] = source
+}
+
+// IsReference returns true if value is a secret reference
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, Scheme)
+}
+
+// Resolve returns the secret a reference points to and masks it from now on
+// Values that are no reference are returned unchanged
+func Resolve(ctx context.Context, value string) (string, error) {
+	if !IsReference(value) {
+		return value, nil
+	}
+	name, path, _ := strings.Cut(strings.TrimPrefix(value, Scheme), "/")
+	mu.RLock()
+	source, ok := sources[name]
+	mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("%w %q in %s", ErrUnknownSource, name, value)
+	}
+	if path == "" {
+		return "", fmt.Errorf("secret reference %s has no path", value)
+	}
+	secret, err := source.Resolve(ctx, path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", value, err)
+	}
+	addMasked(secret)
+	return secret, nil
+}
+
+// ResolveAll replaces the references among values with their secrets, all failures are reported in one error
+func ResolveAll(ctx context.Context, values map[string]string) error {
+	var errs []error
+	for key, value := range values {
+		secret, err := Resolve(ctx, value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+			continue
+		}
+		values[key] = secret
+	}
+	// Sort for a stable message, values is a map
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	return errors.Join(errs...)
+}
+
+// Mask replaces every resolved secret in s with Masked
+func Mask(s string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, secret := range resolved {
+		s = strings.ReplaceAll(s, secret, Masked)
+	}
+	return s
+}
+
+// FileSource reads the secret from a file, a trailing newline is removed
+func FileSource() Source {
+	return SourceFunc(func(_ context.Context, path string) (string, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	})
+}
+
+// EnvSource reads the secret from an environment variable, which has to be set
+func EnvSource() Source {
+	return SourceFunc(func(_ context.Context, name string) (string, error) {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		return value, nil
+	})
+}
+
+// KubernetesSource reads the secret from a key of a Kubernetes Secret, the path is <namespace>/<name>/<key>
+func KubernetesSource(secrets corev1client.SecretsGetter) Source {
+	return SourceFunc(func(ctx context.Context, path string) (string, error) {
+		parts := strings.Split(path, "/")
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			return "", fmt.Errorf("expected <namespace>/<name>/<key>, got %s", path)
+		}
+		secret, err := secrets.Secrets(parts[0]).Get(ctx, parts[1], metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		data, ok := secret.Data[parts[2]]
+		if !ok {
+			return "", fmt.Errorf("secret %s/%s has no key %s", parts[0], parts[1], parts[2])
+		}
+		return string(data), nil
+	})
+}
+
+// PlaceholderSource returns a placeholder instead of the secret, e.g. for k8s references in dry runs without a cluster
+// The placeholder is an https URL, "https://dry-run.invalid/<name>/<path>", so that values checked as URLs are valid too
+func PlaceholderSource(name string) Source {
+	return SourceFunc(func(_ context.Context, path string) (string, error) {
+		return "https://dry-run.invalid/" + name + "/" + path, nil
+	})
+}
+
+// MaskingWriter masks resolved secrets in everything written to it
+// Output is passed on line by line, so that a secret split across writes is masked as well
+type MaskingWriter struct {
+	mu      sync.Mutex
+	w       io.Writer
+	pending []byte
+}
+
+// NewMaskingWriter returns a writer that masks resolved secrets before writing to w
+func NewMaskingWriter(w io.Writer) *MaskingWriter {
+	return &MaskingWriter{w: w}
+}
+
+// Write masks and writes the complete lines of p, the rest is kept until the next newline or Flush
+func (m *MaskingWriter) Write(p []byte) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pending = append(m.pending, p...)
+	end := bytes.LastIndexByte(m.pending, '\n')
+	if end < 0 {
+		return len(p), nil
+	}
+	lines := m.pending[:end+1]
+	m.pending = append([]byte(nil), m.pending[end+1:]...)
+	if _, err := io.WriteString(m.w, Mask(string(lines))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush masks and writes an incomplete last line
+func (m *MaskingWriter) Flush() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.pending) == 0 {
+		return nil
+	}
+	_, err := io.WriteString(m.w, Mask(string(m.pending)))
+	m.pending = nil
+	return err
+}
+
+// addMasked masks secret and each of its lines, output is masked line by line
+func addMasked(secret string) {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, value := range append(strings.Split(secret, "\n"), secret) {
+		value = strings.TrimSpace(value)
+		if len(value) < minMaskedLength || slices.Contains(resolved, value) {
+			continue
+		}
+		resolved = append(resolved, value)
+	}
+	sort.SliceStable(resolved, func(i, j int) bool { return len(resolved[i]) > len(resolved[j]) })
+}
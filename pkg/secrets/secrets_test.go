@@ -0,0 +1,116 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSecrets(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Secrets Package Suite")
+}
+
+var _ = Describe("Secrets", func() {
+	ctx := context.Background()
+
+	BeforeEach(func() {
+		mu.Lock()
+		resolved = nil
+		mu.Unlock()
+	})
+
+	It("should return values that are no reference unchanged", func() {
+		Expect(Resolve(ctx, "https://rekor.example.com")).To(Equal("https://rekor.example.com"))
+		Expect(Mask("https://rekor.example.com")).To(Equal("https://rekor.example.com"))
+	})
+
+	It("should resolve and mask file and env references", func() {
+		file := filepath.Join(GinkgoT().TempDir(), "password")
+		Expect(os.WriteFile(file, []byte("file-secret\n"), 0o600)).To(Succeed())
+		GinkgoT().Setenv("SECRETS_TEST_TOKEN", "env-secret")
+
+		Expect(Resolve(ctx, "secret://file/"+file)).To(Equal("file-secret"))
+		Expect(Resolve(ctx, "secret://env/SECRETS_TEST_TOKEN")).To(Equal("env-secret"))
+		Expect(Mask("password=file-secret token=env-secret")).To(Equal("password=***** token=*****"))
+	})
+
+	It("should resolve k8s references once the source is registered", func() {
+		clientset := fake.NewSimpleClientset(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "keycloak-system", Name: "keycloak"},
+			Data:       map[string][]byte{"password": []byte("k8s-secret")},
+		})
+		_, err := Resolve(ctx, "secret://k8s/keycloak-system/keycloak/password")
+		Expect(err).To(MatchError(ErrUnknownSource))
+
+		Register("k8s", KubernetesSource(clientset.CoreV1()))
+		DeferCleanup(func() {
+			mu.Lock()
+			delete(sources, "k8s")
+			mu.Unlock()
+		})
+		Expect(Resolve(ctx, "secret://k8s/keycloak-system/keycloak/password")).To(Equal("k8s-secret"))
+
+		_, err = Resolve(ctx, "secret://k8s/keycloak-system/keycloak/token")
+		Expect(err).To(MatchError(ContainSubstring("secret keycloak-system/keycloak has no key token")))
+		_, err = Resolve(ctx, "secret://k8s/keycloak/password")
+		Expect(err).To(MatchError(ContainSubstring("expected <namespace>/<name>/<key>, got keycloak/password")))
+	})
+
+	It("should resolve references of a placeholder source to a masked placeholder", func() {
+		Register("k8s", PlaceholderSource("k8s"))
+		DeferCleanup(func() {
+			mu.Lock()
+			delete(sources, "k8s")
+			mu.Unlock()
+		})
+		value, err := Resolve(ctx, "secret://k8s/keycloak-system/keycloak/password")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(Equal("https://dry-run.invalid/k8s/keycloak-system/keycloak/password"))
+		Expect(Mask("OIDC_PASSWORD=" + value)).To(Equal("OIDC_PASSWORD=" + Masked))
+	})
+
+	It("should report all failures of ResolveAll", func() {
+		GinkgoT().Setenv("SECRETS_TEST_USER", "jdoe-secret")
+		values := map[string]string{
+			"OIDC_USER":     "secret://env/SECRETS_TEST_USER",
+			"OIDC_PASSWORD": "secret://env/SECRETS_TEST_MISSING",
+			"OIDC_TOKEN":    "secret://vault/token",
+			"OIDC_REALM":    "trusted-artifact-signer",
+		}
+		err := ResolveAll(ctx, values)
+		Expect(err).To(MatchError(`OIDC_PASSWORD: failed to resolve secret://env/SECRETS_TEST_MISSING: environment variable SECRETS_TEST_MISSING is not set
+OIDC_TOKEN: unknown secret source "vault" in secret://vault/token`))
+		Expect(values).To(HaveKeyWithValue("OIDC_USER", "jdoe-secret"))
+		Expect(values).To(HaveKeyWithValue("OIDC_REALM", "trusted-artifact-signer"))
+	})
+
+	It("should not mask short values", func() {
+		GinkgoT().Setenv("SECRETS_TEST_FLAG", "on")
+		Expect(Resolve(ctx, "secret://env/SECRETS_TEST_FLAG")).To(Equal("on"))
+		Expect(Mask("mode on")).To(Equal("mode on"))
+	})
+
+	It("should mask secrets split across writes", func() {
+		GinkgoT().Setenv("SECRETS_TEST_PASSWORD", "hunter2-secret")
+		Expect(Resolve(ctx, "secret://env/SECRETS_TEST_PASSWORD")).To(Equal("hunter2-secret"))
+
+		var out bytes.Buffer
+		w := NewMaskingWriter(&out)
+		_, _ = w.Write([]byte("login with hunter2-"))
+		Expect(out.String()).To(BeEmpty())
+		_, _ = w.Write([]byte("secret\nretry with hunter2"))
+		_, _ = w.Write([]byte("-secret"))
+		Expect(out.String()).To(Equal("login with *****\n"))
+		Expect(w.Flush()).To(Succeed())
+		Expect(out.String()).To(Equal("login with *****\nretry with *****"))
+	})
+})
@@ -0,0 +1,243 @@
+package verifier
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/asn1"
+	"fmt"
+	"slices"
+	"strings"
+
+	. "github.com/onsi/gomega"
+	"github.com/petrpinkas/config-examples/pkg/clients"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// oidEmailAddress is the emailAddress attribute of a certificate subject
+var oidEmailAddress = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 1}
+
+// CertificateSubject holds the subject fields configured for a certificate
+// Empty fields are not checked
+type CertificateSubject struct {
+	CommonName   string
+	Organization string
+	Email        string
+}
+
+// String returns "CN=...,O=...,email=..."
+func (s CertificateSubject) String() string {
+	var parts []string
+	if s.CommonName != "" {
+		parts = append(parts, "CN="+s.CommonName)
+	}
+	if s.Organization != "" {
+		parts = append(parts, "O="+s.Organization)
+	}
+	if s.Email != "" {
+		parts = append(parts, "email="+s.Email)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Mismatches returns a description of every field of cert that does not match
+// The email is accepted in the subject emailAddress attribute or as email SAN
+func (s CertificateSubject) Mismatches(cert *x509.Certificate) []string {
+	var mismatches []string
+	if s.CommonName != "" && cert.Subject.CommonName != s.CommonName {
+		mismatches = append(mismatches, fmt.Sprintf("CN: expected %q, got %q", s.CommonName, cert.Subject.CommonName))
+	}
+	if s.Organization != "" && !slices.Contains(cert.Subject.Organization, s.Organization) {
+		mismatches = append(mismatches, fmt.Sprintf("O: expected %q, got %q", s.Organization, cert.Subject.Organization))
+	}
+	if s.Email != "" {
+		emails := slices.Clone(cert.EmailAddresses)
+		for _, name := range cert.Subject.Names {
+			if value, ok := name.Value.(string); ok && name.Type.Equal(oidEmailAddress) {
+				emails = append(emails, value)
+			}
+		}
+		if !slices.Contains(emails, s.Email) {
+			mismatches = append(mismatches, fmt.Sprintf("email: expected %q, got %q", s.Email, emails))
+		}
+	}
+	return mismatches
+}
+
+// CertificateExpectation describes the subject expected for a certificate of a component
+type CertificateExpectation struct {
+	Component string
+	// Description names the certificate, e.g. "TSA leaf certificate"
+	Description string
+	// Index is the position in the PEM bundle; negative values count from the end (-1 is the last certificate)
+	Index   int
+	Subject CertificateSubject
+}
+
+// CertificateCheck is the result of checking a certificate against its expectation
+type CertificateCheck struct {
+	Expectation CertificateExpectation
+	Ref         SecretRef
+	// Mismatches lists the subject fields that do not match
+	Mismatches []string
+	// Err is set if the certificate could not be read
+	Err error
+}
+
+// OK returns true if the certificate was read and matches
+func (c CertificateCheck) OK() bool {
+	return c.Err == nil && len(c.Mismatches) == 0
+}
+
+// String returns a one line summary of the check
+func (c CertificateCheck) String() string {
+	result := "ok"
+	switch {
+	case c.Err != nil:
+		result = c.Err.Error()
+	case len(c.Mismatches) > 0:
+		result = strings.Join(c.Mismatches, "; ")
+	}
+	return fmt.Sprintf("%s: %s (%s): %s", c.Expectation.Component, c.Expectation.Description, c.Ref, result)
+}
+
+// ExpectedCertificates returns the certificate subjects configured in a rendered config:
+// spec.fulcio.certificate and spec.tsa.signer.certificateChain of a Securesign,
+// or spec.certificate of a Fulcio and spec.signer.certificateChain of a TimestampAuthority
+func ExpectedCertificates(rendered *unstructured.Unstructured) []CertificateExpectation {
+	var fulcioSpec, tsaSpec []string
+	switch rendered.GetKind() {
+	case securesignGVK.Kind:
+		fulcioSpec = []string{"spec", Fulcio.SpecField}
+		tsaSpec = []string{"spec", TSA.SpecField}
+	case fulcioGVK.Kind:
+		fulcioSpec = []string{"spec"}
+	case tsaGVK.Kind:
+		tsaSpec = []string{"spec"}
+	default:
+		return nil
+	}
+
+	var expected []CertificateExpectation
+	if fulcioSpec != nil {
+		if subject, ok := subjectAt(rendered.Object, append(fulcioSpec, "certificate")...); ok {
+			expected = append(expected, CertificateExpectation{Component: Fulcio.Name, Description: "Fulcio CA certificate", Subject: subject})
+		}
+	}
+	if tsaSpec != nil {
+		chain := append(tsaSpec, "signer", "certificateChain")
+		if subject, ok := subjectAt(rendered.Object, append(chain, "leafCA")...); ok {
+			expected = append(expected, CertificateExpectation{Component: TSA.Name, Description: "TSA leaf certificate", Subject: subject})
+		}
+		intermediates, _, _ := unstructured.NestedSlice(rendered.Object, append(chain, "intermediateCA")...)
+		for i, intermediate := range intermediates {
+			intermediateMap, ok := intermediate.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if subject, ok := subjectAt(intermediateMap); ok {
+				expected = append(expected, CertificateExpectation{Component: TSA.Name,
+					Description: fmt.Sprintf("TSA intermediate certificate %d", i+1), Index: i + 1, Subject: subject})
+			}
+		}
+		if subject, ok := subjectAt(rendered.Object, append(chain, "rootCA")...); ok {
+			expected = append(expected, CertificateExpectation{Component: TSA.Name, Description: "TSA root certificate", Index: -1, Subject: subject})
+		}
+	}
+	return expected
+}
+
+// CheckCertificates reads the Fulcio CA and TSA certificate chain of a Securesign (or a single component CR)
+// and compares their subjects with the expectations, see ExpectedCertificates
+func CheckCertificates(ctx context.Context, cli client.Client, ref Ref, expected []CertificateExpectation) ([]CertificateCheck, error) {
+	components, err := discoverComponents(ctx, cli, ref)
+	if err != nil {
+		return nil, err
+	}
+	objects := map[string]*unstructured.Unstructured{}
+	for _, d := range components {
+		objects[d.component.Name] = d.obj
+	}
+
+	checks := make([]CertificateCheck, 0, len(expected))
+	for _, expectation := range expected {
+		check := CertificateCheck{Expectation: expectation}
+		obj := objects[expectation.Component]
+		if obj == nil {
+			check.Err = fmt.Errorf("component %s not found", expectation.Component)
+			checks = append(checks, check)
+			continue
+		}
+
+		secret := certificateSecret(expectation.Component, obj)
+		check.Ref = secret.Ref
+		if check.Err = secret.Err; check.Err == nil {
+			var data []byte
+			data, check.Err = getSecretValue(ctx, cli, obj.GetNamespace(), secret.Ref)
+			if check.Err != nil && !isSecretCheckError(check.Err) {
+				return nil, check.Err
+			}
+			if check.Err == nil {
+				var cert *x509.Certificate
+				if cert, check.Err = certificateAt(data, expectation.Index); check.Err == nil {
+					check.Mismatches = expectation.Subject.Mismatches(cert)
+				}
+			}
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+// VerifyCertificates compares the component certificates with the subjects configured in the rendered
+// config and fails the current spec listing every mismatch
+func VerifyCertificates(ctx context.Context, cli client.Client, namespace, name string, gvk schema.GroupVersionKind, rendered *unstructured.Unstructured) {
+	checks, err := CheckCertificates(ctx, cli, Ref{GVK: gvk, Namespace: namespace, Name: name}, ExpectedCertificates(rendered))
+	Expect(err).NotTo(HaveOccurred())
+
+	var failed []string
+	for _, check := range checks {
+		if !check.OK() {
+			failed = append(failed, check.String())
+		}
+	}
+	Expect(failed).To(BeEmpty(), "certificates do not match the config:\n%s", strings.Join(failed, "\n"))
+}
+
+// certificateSecret returns the secret holding the certificates of a component
+func certificateSecret(component string, obj *unstructured.Unstructured) SecretCheck {
+	switch component {
+	case Fulcio.Name:
+		return fulcioSecrets(obj)[0]
+	case TSA.Name:
+		return tsaSecrets(obj)[0]
+	default:
+		return SecretCheck{Err: fmt.Errorf("no certificates known for component %s", component)}
+	}
+}
+
+// certificateAt returns the certificate at index of a PEM bundle, negative indexes count from the end
+func certificateAt(data []byte, index int) (*x509.Certificate, error) {
+	certs, err := clients.ParseCertificates(data)
+	if err != nil {
+		return nil, err
+	}
+	i := index
+	if i < 0 {
+		i += len(certs)
+	}
+	if i < 0 || i >= len(certs) {
+		return nil, fmt.Errorf("certificate %d not found, bundle has %d certificate(s)", index, len(certs))
+	}
+	return certs[i], nil
+}
+
+// subjectAt reads commonName, organizationName and organizationEmail from a nested map
+func subjectAt(obj map[string]interface{}, path ...string) (CertificateSubject, bool) {
+	var subject CertificateSubject
+	subject.CommonName, _, _ = unstructured.NestedString(obj, append(path, "commonName")...)
+	subject.Organization, _, _ = unstructured.NestedString(obj, append(path, "organizationName")...)
+	subject.Email, _, _ = unstructured.NestedString(obj, append(path, "organizationEmail")...)
+	return subject, subject != CertificateSubject{}
+}
@@ -0,0 +1,131 @@
+package verifier
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/petrpinkas/config-examples/pkg/clients"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// certificatePEM returns a self-signed PEM certificate with the given subject
+func certificatePEM(subject CertificateSubject) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber:   big.NewInt(1),
+		Subject:        pkix.Name{CommonName: subject.CommonName, Organization: []string{subject.Organization}},
+		EmailAddresses: []string{subject.Email},
+		NotBefore:      time.Now(),
+		NotAfter:       time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+// renderedSecuresign is the certificate part of the scenario templates
+const renderedSecuresign = `
+kind: Securesign
+apiVersion: rhtas.redhat.com/v1alpha1
+metadata:
+  name: sample
+spec:
+  fulcio:
+    certificate:
+      commonName: fulcio.hostname
+      organizationEmail: jdoe@redhat.com
+      organizationName: Red Hat
+  tsa:
+    signer:
+      certificateChain:
+        intermediateCA:
+          - commonName: tsa.hostname-intermediate
+            organizationEmail: jdoe@redhat.com
+            organizationName: Red Hat
+        leafCA:
+          commonName: tsa.hostname-leaf
+          organizationEmail: jdoe@redhat.com
+          organizationName: Red Hat
+        rootCA:
+          commonName: tsa.hostname-root
+          organizationEmail: jdoe@redhat.com
+          organizationName: Red Hat
+`
+
+var _ = Describe("Certificates", func() {
+	var rendered *unstructured.Unstructured
+
+	BeforeEach(func() {
+		data := map[string]interface{}{}
+		Expect(yaml.Unmarshal([]byte(renderedSecuresign), &data)).To(Succeed())
+		rendered = &unstructured.Unstructured{Object: data}
+	})
+
+	It("should read expected subjects from the rendered config", func() {
+		expected := ExpectedCertificates(rendered)
+		Expect(expected).To(HaveLen(4))
+		Expect(expected[0]).To(Equal(CertificateExpectation{Component: "fulcio", Description: "Fulcio CA certificate",
+			Subject: CertificateSubject{CommonName: "fulcio.hostname", Organization: "Red Hat", Email: "jdoe@redhat.com"}}))
+		Expect(expected[1].Description).To(Equal("TSA leaf certificate"))
+		Expect(expected[2].Index).To(Equal(1))
+		Expect(expected[3].Index).To(Equal(-1))
+		Expect(expected[3].Subject.String()).To(Equal("CN=tsa.hostname-root,O=Red Hat,email=jdoe@redhat.com"))
+	})
+
+	It("should compare the live certificates with the config", func(ctx SpecContext) {
+		securesign := newSecuresign("ns", "sample")
+		securesign.SetUID("securesign-uid")
+		fulcio := owned(fulcioGVK, "sample", securesign)
+		setField(fulcio, ref("fulcio-cert", "cert"), "status", "certificate", "caRef")
+		tsa := owned(tsaGVK, "sample", securesign)
+		setField(tsa, ref("tsa-chain", "certificateChain"), "status", "signer", "certificateChain", "certificateChainRef")
+
+		chain := certificatePEM(CertificateSubject{CommonName: "tsa.hostname-leaf", Organization: "Red Hat", Email: "jdoe@redhat.com"}) +
+			certificatePEM(CertificateSubject{CommonName: "tsa.hostname-intermediate", Organization: "Red Hat", Email: "jdoe@redhat.com"}) +
+			certificatePEM(CertificateSubject{CommonName: "tsa.hostname-root", Organization: "Other", Email: "jdoe@redhat.com"})
+		cli := fake.NewClientBuilder().WithObjects(securesign, fulcio, tsa,
+			secret("fulcio-cert", map[string]string{"cert": certificatePEM(CertificateSubject{CommonName: "fulcio.hostname", Organization: "Red Hat", Email: "jdoe@redhat.com"})}),
+			secret("tsa-chain", map[string]string{"certificateChain": chain}),
+		).Build()
+
+		checks, err := CheckCertificates(ctx, cli, Ref{GVK: securesignGVK, Namespace: "ns", Name: "sample"}, ExpectedCertificates(rendered))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checks).To(HaveLen(4))
+		Expect(checks[0].OK()).To(BeTrue(), checks[0].String())
+		Expect(checks[1].OK()).To(BeTrue(), checks[1].String())
+		Expect(checks[2].OK()).To(BeTrue(), checks[2].String())
+		Expect(checks[3].OK()).To(BeFalse())
+		Expect(checks[3].String()).To(Equal(`tsa: TSA root certificate (tsa-chain/certificateChain): O: expected "Red Hat", got ["Other"]`))
+
+		failures := InterceptGomegaFailures(func() {
+			VerifyCertificates(ctx, cli, "ns", "sample", securesignGVK, rendered)
+		})
+		Expect(failures).To(HaveLen(1))
+		Expect(failures[0]).To(ContainSubstring("TSA root certificate"))
+	})
+
+	It("should accept the email in the subject", func() {
+		cert := &x509.Certificate{Subject: pkix.Name{
+			CommonName: "fulcio.hostname",
+			Names:      []pkix.AttributeTypeAndValue{{Type: oidEmailAddress, Value: "jdoe@redhat.com"}},
+		}}
+		Expect(CertificateSubject{CommonName: "fulcio.hostname", Email: "jdoe@redhat.com"}.Mismatches(cert)).To(BeEmpty())
+		Expect(CertificateSubject{Email: "other@redhat.com"}.Mismatches(cert)).To(HaveLen(1))
+	})
+
+	It("should reject data without certificates", func() {
+		_, err := clients.ParseCertificates([]byte("not a certificate"))
+		Expect(err).To(HaveOccurred())
+	})
+})
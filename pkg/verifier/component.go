@@ -0,0 +1,389 @@
+package verifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	deploymentGVK = schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	serviceGVK    = schema.GroupVersionKind{Version: "v1", Kind: "Service"}
+	routeGVK      = schema.GroupVersionKind{Group: "route.openshift.io", Version: "v1", Kind: "Route"}
+)
+
+// Component describes an RHTAS component the operator creates for a Securesign
+type Component struct {
+	// Name is the short component name, e.g. "fulcio"
+	Name string
+	// GVK is the kind of the component CR
+	GVK schema.GroupVersionKind
+	// SpecField is the field of Securesign.spec configuring the component
+	SpecField string
+	// HealthPath is the well-known path probed on the component URL, empty if it serves no HTTP API
+	HealthPath string
+}
+
+// Components returns all known RHTAS components in rollout order
+func Components() []Component {
+	return []Component{Trillian, Fulcio, Rekor, CTlog, TSA, TUF}
+}
+
+// ComponentFor returns the component with the given CR kind
+func ComponentFor(gvk schema.GroupVersionKind) (Component, bool) {
+	for _, c := range Components() {
+		if c.GVK.GroupKind() == gvk.GroupKind() {
+			return c, true
+		}
+	}
+	return Component{}, false
+}
+
+// HasComponents returns true if components can be verified for the kind,
+// i.e. for a Securesign or a single component CR
+func HasComponents(gvk schema.GroupVersionKind) bool {
+	_, ok := ComponentFor(gvk)
+	return ok || gvk.GroupKind() == securesignGVK.GroupKind()
+}
+
+// ResourceStatus is the state of a single resource belonging to a component
+type ResourceStatus struct {
+	Kind    string
+	Name    string
+	Ready   bool
+	Message string
+}
+
+// ComponentStatus is the state of a component CR and the workloads it owns
+type ComponentStatus struct {
+	Component Component
+	// Found is false if the operator has not created the component CR (yet)
+	Found bool
+	// Resources contains the component CR first, followed by its Deployments, Services and Routes
+	Resources []ResourceStatus
+}
+
+// Ready returns true if the component CR exists and all its resources are ready
+func (s ComponentStatus) Ready() bool {
+	if !s.Found {
+		return false
+	}
+	for _, r := range s.Resources {
+		if !r.Ready {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckComponents returns the state of the components of a Securesign, or of a single component CR
+// Components are discovered through owner references; a component configured in the Securesign spec
+// that was not created yet is reported as not found
+func CheckComponents(ctx context.Context, cli client.Client, ref Ref) ([]ComponentStatus, error) {
+	components, err := discoverComponents(ctx, cli, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]ComponentStatus, 0, len(components))
+	for _, d := range components {
+		if d.obj == nil {
+			statuses = append(statuses, ComponentStatus{Component: d.component})
+			continue
+		}
+		status, err := componentStatus(ctx, cli, d.component, d.obj)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// WaitForComponents waits until all components of a Securesign (or a single component CR) are ready
+// On timeout the returned error wraps ErrWaitTimeout and contains the component status table
+func WaitForComponents(ctx context.Context, cli client.Client, ref Ref, opts WaitOptions) ([]ComponentStatus, error) {
+	opts = withDefaults(opts)
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	var (
+		statuses []ComponentStatus
+		lastErr  error
+		attempt  int
+	)
+	err := pollWithBackoff(ctx, opts, func() (bool, error) {
+		attempt++
+		statuses, lastErr = CheckComponents(ctx, cli, ref)
+		done := lastErr == nil && allReady(statuses)
+		if opts.OnProgress != nil {
+			opts.OnProgress(Progress{Ref: ref, Attempt: attempt, Elapsed: time.Since(start), Err: lastErr, Done: done,
+				Object: componentsAsObject(statuses)})
+		}
+		if lastErr != nil && !IsRetryable(lastErr) {
+			return false, lastErr
+		}
+		return done, nil
+	}, func(error) error {
+		elapsed := time.Since(start).Round(time.Second)
+		if lastErr != nil {
+			return fmt.Errorf("%w after %s waiting for components of %s: %w", ErrWaitTimeout, elapsed, ref, lastErr)
+		}
+		return fmt.Errorf("%w after %s waiting for components of %s:\n%s",
+			ErrWaitTimeout, elapsed, ref, FormatComponentStatus(statuses))
+	})
+	return statuses, err
+}
+
+// VerifyComponents waits for all components of a Securesign (or a single component CR) to be ready
+// and fails the current spec with a per-component status table otherwise
+func VerifyComponents(ctx context.Context, cli client.Client, namespace, name string, gvk schema.GroupVersionKind) {
+	opts := DefaultWaitOptions()
+	opts.OnProgress = logStateChanges()
+	ref := Ref{GVK: gvk, Namespace: namespace, Name: name}
+	_, err := WaitForComponents(ctx, cli, ref, opts)
+	if err != nil {
+		collectOnFailure(ctx, cli, ref)
+	}
+	Expect(err).NotTo(HaveOccurred())
+}
+
+// FormatComponentStatus renders statuses as a table with one row per resource
+func FormatComponentStatus(statuses []ComponentStatus) string {
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COMPONENT\tKIND\tNAME\tREADY\tMESSAGE")
+	for _, s := range statuses {
+		if !s.Found {
+			fmt.Fprintf(w, "%s\t%s\t-\tfalse\tnot created\n", s.Component.Name, s.Component.GVK.Kind)
+			continue
+		}
+		for _, r := range s.Resources {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\n", s.Component.Name, r.Kind, r.Name, r.Ready, r.Message)
+		}
+	}
+	_ = w.Flush()
+	return sb.String()
+}
+
+// discovered is a component together with its CR, obj is nil if the CR was not created (yet)
+type discovered struct {
+	component Component
+	obj       *unstructured.Unstructured
+}
+
+// discoverComponents returns the expected components of a Securesign, or the single component for a component CR
+func discoverComponents(ctx context.Context, cli client.Client, ref Ref) ([]discovered, error) {
+	root, err := Get(ctx, cli, ref.Namespace, ref.Name, ref.GVK)
+	if err != nil {
+		return nil, err
+	}
+
+	if component, ok := ComponentFor(ref.GVK); ok {
+		return []discovered{{component: component, obj: root}}, nil
+	}
+	if ref.GVK.GroupKind() != securesignGVK.GroupKind() {
+		return nil, fmt.Errorf("no components known for kind %s", ref.GVK.Kind)
+	}
+
+	var components []discovered
+	for _, component := range Components() {
+		child, err := findChild(ctx, cli, component, root)
+		if err != nil {
+			return nil, err
+		}
+		if child == nil {
+			// Only components configured in the spec are expected
+			if _, configured, _ := unstructured.NestedFieldNoCopy(root.Object, "spec", component.SpecField); !configured {
+				continue
+			}
+		}
+		components = append(components, discovered{component: component, obj: child})
+	}
+	return components, nil
+}
+
+// findChild returns the component CR owned by the Securesign, nil if there is none
+// CRs named like the Securesign are accepted as well, in case owner references are not set
+func findChild(ctx context.Context, cli client.Client, component Component, securesign *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	items, err := list(ctx, cli, component.GVK, securesign.GetNamespace())
+	if err != nil {
+		if errors.Is(err, ErrNoKindMatch) {
+			return nil, nil // CRD not installed, the component is not supported by this operator version
+		}
+		return nil, err
+	}
+
+	var byName *unstructured.Unstructured
+	for i := range items {
+		if isOwnedBy(&items[i], securesign.GetUID()) {
+			return &items[i], nil
+		}
+		if items[i].GetName() == securesign.GetName() {
+			byName = &items[i]
+		}
+	}
+	return byName, nil
+}
+
+// componentStatus checks a component CR and the Deployments, Services and Routes it owns
+func componentStatus(ctx context.Context, cli client.Client, component Component, cr *unstructured.Unstructured) (ComponentStatus, error) {
+	status := ComponentStatus{Component: component, Found: true}
+	status.Resources = append(status.Resources, ResourceStatus{
+		Kind:    cr.GetKind(),
+		Name:    cr.GetName(),
+		Ready:   IsReady(cr),
+		Message: DescribeConditions(cr),
+	})
+
+	checks := []struct {
+		gvk   schema.GroupVersionKind
+		check func(*unstructured.Unstructured) (bool, string)
+	}{
+		{deploymentGVK, deploymentReady},
+		{serviceGVK, serviceReady},
+		{routeGVK, routeReady},
+	}
+	for _, c := range checks {
+		items, err := list(ctx, cli, c.gvk, cr.GetNamespace())
+		if err != nil {
+			if c.gvk == routeGVK && errors.Is(err, ErrNoKindMatch) {
+				continue // Not OpenShift
+			}
+			return status, err
+		}
+		for i := range items {
+			if !isOwnedBy(&items[i], cr.GetUID()) {
+				continue
+			}
+			ready, message := c.check(&items[i])
+			status.Resources = append(status.Resources, ResourceStatus{
+				Kind:    c.gvk.Kind,
+				Name:    items[i].GetName(),
+				Ready:   ready,
+				Message: message,
+			})
+		}
+	}
+	return status, nil
+}
+
+// deploymentReady checks that all replicas of the current generation are ready
+func deploymentReady(obj *unstructured.Unstructured) (bool, string) {
+	replicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	if !found {
+		replicas = 1
+	}
+	readyReplicas, _, _ := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+	updatedReplicas, _, _ := unstructured.NestedInt64(obj.Object, "status", "updatedReplicas")
+	observed, _, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+
+	message := fmt.Sprintf("%d/%d replicas ready", readyReplicas, replicas)
+	if observed < obj.GetGeneration() {
+		return false, message + ", rollout pending"
+	}
+	return readyReplicas >= replicas && updatedReplicas >= replicas, message
+}
+
+// serviceReady reports the service type, a Service needs no rollout
+func serviceReady(obj *unstructured.Unstructured) (bool, string) {
+	serviceType, _, _ := unstructured.NestedString(obj.Object, "spec", "type")
+	if serviceType == "" {
+		serviceType = "ClusterIP"
+	}
+	return true, serviceType
+}
+
+// routeReady checks that the route was admitted by a router
+func routeReady(obj *unstructured.Unstructured) (bool, string) {
+	host, _, _ := unstructured.NestedString(obj.Object, "spec", "host")
+	ingresses, _, _ := unstructured.NestedSlice(obj.Object, "status", "ingress")
+	for _, ingress := range ingresses {
+		ingressMap, ok := ingress.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		conditions, _, _ := unstructured.NestedSlice(ingressMap, "conditions")
+		for _, cond := range conditions {
+			condMap, ok := cond.(map[string]interface{})
+			if ok && condMap["type"] == "Admitted" && condMap["status"] == "True" {
+				return true, host
+			}
+		}
+	}
+	return false, fmt.Sprintf("%s not admitted", host)
+}
+
+// list returns all resources of a kind in a namespace
+func list(ctx context.Context, cli client.Client, gvk schema.GroupVersionKind, namespace string) ([]unstructured.Unstructured, error) {
+	items := &unstructured.UnstructuredList{}
+	items.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := cli.List(ctx, items, client.InNamespace(namespace)); err != nil {
+		return nil, newGetError(gvk, namespace, "*", err)
+	}
+	return items.Items, nil
+}
+
+func isOwnedBy(obj *unstructured.Unstructured, uid types.UID) bool {
+	if uid == "" {
+		return false
+	}
+	for _, owner := range obj.GetOwnerReferences() {
+		if owner.UID == uid {
+			return true
+		}
+	}
+	return false
+}
+
+func allReady(statuses []ComponentStatus) bool {
+	for _, s := range statuses {
+		if !s.Ready() {
+			return false
+		}
+	}
+	return len(statuses) > 0
+}
+
+// componentsAsObject summarizes component readiness as status conditions, e.g. "fulcio=True",
+// so that Progress.String() and logStateChanges() work for component waits
+func componentsAsObject(statuses []ComponentStatus) *unstructured.Unstructured {
+	if statuses == nil {
+		return nil
+	}
+	conditions := make([]interface{}, 0, len(statuses))
+	for _, s := range statuses {
+		status, reason := "True", ""
+		if !s.Ready() {
+			status, reason = "False", notReadyReason(s)
+		}
+		conditions = append(conditions, map[string]interface{}{"type": s.Component.Name, "status": status, "reason": reason})
+	}
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	_ = unstructured.SetNestedSlice(obj.Object, conditions, "status", "conditions")
+	return obj
+}
+
+// notReadyReason names the first resource of a component that is not ready
+func notReadyReason(s ComponentStatus) string {
+	if !s.Found {
+		return "not created"
+	}
+	for _, r := range s.Resources {
+		if !r.Ready {
+			return fmt.Sprintf("%s %s: %s", r.Kind, r.Name, r.Message)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,162 @@
+package verifier
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// owned returns an object of the given kind owned by owner
+func owned(gvk schema.GroupVersionKind, name string, owner *unstructured.Unstructured) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetNamespace(owner.GetNamespace())
+	obj.SetName(name)
+	obj.SetUID(types.UID(gvk.Kind + "-" + name))
+	obj.SetOwnerReferences([]metav1.OwnerReference{{
+		APIVersion: owner.GetAPIVersion(), Kind: owner.GetKind(), Name: owner.GetName(), UID: owner.GetUID(),
+	}})
+	return obj
+}
+
+// withConditions sets status conditions on obj
+func withConditions(obj *unstructured.Unstructured, conditions ...map[string]interface{}) *unstructured.Unstructured {
+	items := make([]interface{}, len(conditions))
+	for i, c := range conditions {
+		items[i] = c
+	}
+	Expect(unstructured.SetNestedSlice(obj.Object, items, "status", "conditions")).To(Succeed())
+	return obj
+}
+
+// deployment returns a Deployment owned by owner with readyReplicas out of 1
+func deployment(name string, owner *unstructured.Unstructured, readyReplicas int64) *unstructured.Unstructured {
+	obj := owned(deploymentGVK, name, owner)
+	Expect(unstructured.SetNestedField(obj.Object, int64(1), "spec", "replicas")).To(Succeed())
+	Expect(unstructured.SetNestedField(obj.Object, readyReplicas, "status", "readyReplicas")).To(Succeed())
+	Expect(unstructured.SetNestedField(obj.Object, int64(1), "status", "updatedReplicas")).To(Succeed())
+	return obj
+}
+
+var _ = Describe("Components", func() {
+	var (
+		ctx        context.Context
+		securesign *unstructured.Unstructured
+		ref        Ref
+	)
+	ready := map[string]interface{}{"type": "Ready", "status": "True", "reason": "Ready"}
+	creating := map[string]interface{}{"type": "Ready", "status": "False", "reason": "Creating"}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		securesign = newSecuresign("ns", "sample", ready)
+		securesign.SetUID("securesign-uid")
+		Expect(unstructured.SetNestedMap(securesign.Object, map[string]interface{}{
+			"fulcio":   map[string]interface{}{},
+			"trillian": map[string]interface{}{},
+		}, "spec")).To(Succeed())
+		ref = Ref{GVK: securesignGVK, Namespace: "ns", Name: "sample"}
+	})
+
+	It("should discover owned component CRs and their workloads", func() {
+		fulcio := withConditions(owned(fulcioGVK, "sample", securesign), ready)
+		trillian := withConditions(owned(trillianGVK, "sample", securesign), ready)
+		cli := fake.NewClientBuilder().WithObjects(
+			securesign, fulcio, trillian,
+			deployment("fulcio-server", fulcio, 1),
+			owned(serviceGVK, "fulcio-server", fulcio),
+			deployment("trillian-db", trillian, 1),
+			deployment("unrelated", securesign, 0),
+		).Build()
+
+		statuses, err := CheckComponents(ctx, cli, ref)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statuses).To(HaveLen(2))
+		Expect(statuses[0].Component).To(Equal(Trillian))
+		Expect(statuses[1].Component).To(Equal(Fulcio))
+		Expect(statuses[1].Resources).To(ConsistOf(
+			ResourceStatus{Kind: "Fulcio", Name: "sample", Ready: true, Message: "Ready=True (Ready)"},
+			ResourceStatus{Kind: "Deployment", Name: "fulcio-server", Ready: true, Message: "1/1 replicas ready"},
+			ResourceStatus{Kind: "Service", Name: "fulcio-server", Ready: true, Message: "ClusterIP"},
+		))
+		Expect(allReady(statuses)).To(BeTrue())
+	})
+
+	It("should report configured components that were not created", func() {
+		fulcio := withConditions(owned(fulcioGVK, "sample", securesign), ready)
+		cli := fake.NewClientBuilder().WithObjects(securesign, fulcio).Build()
+
+		statuses, err := CheckComponents(ctx, cli, ref)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statuses).To(HaveLen(2))
+		Expect(statuses[0].Found).To(BeFalse())
+		Expect(allReady(statuses)).To(BeFalse())
+		Expect(FormatComponentStatus(statuses)).To(MatchRegexp(`trillian\s+Trillian\s+-\s+false\s+not created`))
+	})
+
+	It("should verify a single component CR", func() {
+		fulcio := withConditions(owned(fulcioGVK, "fulcio-sample", securesign), ready)
+		cli := fake.NewClientBuilder().WithObjects(fulcio, deployment("fulcio-server", fulcio, 1)).Build()
+
+		statuses, err := WaitForComponents(ctx, cli, Ref{GVK: fulcioGVK, Namespace: "ns", Name: "fulcio-sample"}, fastWait(time.Second))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statuses).To(HaveLen(1))
+		Expect(statuses[0].Resources).To(HaveLen(2))
+	})
+
+	It("should time out with a status table naming the resources that are not ready", func() {
+		fulcio := withConditions(owned(fulcioGVK, "sample", securesign), creating)
+		trillian := withConditions(owned(trillianGVK, "sample", securesign), ready)
+		cli := fake.NewClientBuilder().WithObjects(
+			securesign, fulcio, trillian,
+			deployment("fulcio-server", fulcio, 0),
+		).Build()
+
+		var last Progress
+		opts := fastWait(50 * time.Millisecond)
+		opts.OnProgress = func(p Progress) { last = p }
+
+		_, err := WaitForComponents(ctx, cli, ref, opts)
+		Expect(errors.Is(err, ErrWaitTimeout)).To(BeTrue())
+		Expect(err.Error()).To(MatchRegexp(`fulcio\s+Deployment\s+fulcio-server\s+false\s+0/1 replicas ready`))
+		Expect(err.Error()).To(MatchRegexp(`trillian\s+Trillian\s+sample\s+true`))
+		Expect(last.String()).To(ContainSubstring("fulcio=False (Fulcio sample: Ready=False (Creating))"))
+	})
+
+	It("should include the last error on timeout", func() {
+		_, err := WaitForComponents(ctx, failingGetClient(errors.New("boom")), ref, fastWait(50*time.Millisecond))
+		Expect(errors.Is(err, ErrWaitTimeout)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("boom"))
+	})
+
+	It("should check route admission", func() {
+		route := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"host": "fulcio.example.com"},
+			"status": map[string]interface{}{"ingress": []interface{}{map[string]interface{}{
+				"conditions": []interface{}{map[string]interface{}{"type": "Admitted", "status": "True"}},
+			}}},
+		}}
+		admitted, message := routeReady(route)
+		Expect(admitted).To(BeTrue())
+		Expect(message).To(Equal("fulcio.example.com"))
+
+		Expect(unstructured.SetNestedSlice(route.Object, nil, "status", "ingress")).To(Succeed())
+		admitted, message = routeReady(route)
+		Expect(admitted).To(BeFalse())
+		Expect(message).To(Equal("fulcio.example.com not admitted"))
+	})
+
+	It("should know which kinds have components", func() {
+		Expect(HasComponents(securesignGVK)).To(BeTrue())
+		Expect(HasComponents(tsaGVK)).To(BeTrue())
+		Expect(HasComponents(deploymentGVK)).To(BeFalse())
+	})
+})
@@ -0,0 +1,200 @@
+package verifier
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Keys used to express expectations in scenario conf files
+//
+//	expect.condition.Ready=False/Failure
+//	expect.condition.FulcioAvailable=True
+//	expect.observedGeneration=true
+const (
+	ExpectConditionPrefix       = "expect.condition."
+	ExpectObservedGenerationKey = "expect.observedGeneration"
+)
+
+// ConditionExpectation describes an expected status condition
+type ConditionExpectation struct {
+	Type   string
+	Status string
+	// Reason is optional, any reason matches when empty
+	Reason string
+}
+
+// String returns "Type=Status" or "Type=Status/Reason"
+func (e ConditionExpectation) String() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("%s=%s/%s", e.Type, e.Status, e.Reason)
+	}
+	return fmt.Sprintf("%s=%s", e.Type, e.Status)
+}
+
+// Expectations is the set of expectations a resource has to meet
+type Expectations struct {
+	Conditions []ConditionExpectation
+	// ObservedGeneration requires status.observedGeneration >= metadata.generation
+	ObservedGeneration bool
+}
+
+// DefaultExpectations expects the Ready condition to be True
+func DefaultExpectations() Expectations {
+	return Expectations{Conditions: []ConditionExpectation{{Type: "Ready", Status: "True"}}}
+}
+
+// IsZero returns true if there is nothing to check
+func (e Expectations) IsZero() bool {
+	return len(e.Conditions) == 0 && !e.ObservedGeneration
+}
+
+// ExpectsReady returns true if the Ready condition is expected to be True
+func (e Expectations) ExpectsReady() bool {
+	for _, c := range e.Conditions {
+		if c.Type == "Ready" && c.Status == "True" {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns a compact summary, e.g. "Ready=True, FulcioAvailable=True, observedGeneration"
+func (e Expectations) String() string {
+	parts := make([]string, 0, len(e.Conditions)+1)
+	for _, c := range e.Conditions {
+		parts = append(parts, c.String())
+	}
+	if e.ObservedGeneration {
+		parts = append(parts, "observedGeneration")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Unmet returns a description of every expectation obj does not meet, empty if all are met
+func (e Expectations) Unmet(obj *unstructured.Unstructured) []string {
+	if obj == nil {
+		return []string{"resource not found"}
+	}
+
+	var unmet []string
+	for _, expected := range e.Conditions {
+		actual, found := FindCondition(obj, expected.Type)
+		switch {
+		case !found:
+			unmet = append(unmet, fmt.Sprintf("%s: condition not present", expected))
+		case actual.Status != expected.Status || (expected.Reason != "" && actual.Reason != expected.Reason):
+			unmet = append(unmet, fmt.Sprintf("%s: got %s", expected, actual))
+		}
+	}
+
+	if e.ObservedGeneration {
+		generation := obj.GetGeneration()
+		observed, found := observedGeneration(obj)
+		if !found {
+			unmet = append(unmet, "observedGeneration: not reported")
+		} else if observed < generation {
+			unmet = append(unmet, fmt.Sprintf("observedGeneration: %d < generation %d", observed, generation))
+		}
+	}
+	return unmet
+}
+
+// Condition returns a Condition for WaitFor that is met when all expectations are met
+func (e Expectations) Condition() Condition {
+	return func(obj *unstructured.Unstructured) (bool, error) {
+		return len(e.Unmet(obj)) == 0, nil
+	}
+}
+
+// FindCondition returns the status condition of the given type
+func FindCondition(obj *unstructured.Unstructured, conditionType string) (ConditionExpectation, bool) {
+	if obj == nil {
+		return ConditionExpectation{}, false
+	}
+	conditions, found, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if !found || err != nil {
+		return ConditionExpectation{}, false
+	}
+
+	for _, cond := range conditions {
+		condMap, ok := cond.(map[string]interface{})
+		if !ok || condMap["type"] != conditionType {
+			continue
+		}
+		status, _ := condMap["status"].(string)
+		reason, _ := condMap["reason"].(string)
+		return ConditionExpectation{Type: conditionType, Status: status, Reason: reason}, true
+	}
+	return ConditionExpectation{}, false
+}
+
+// ParseExpectations reads expectations from scenario conf values (see ExpectConditionPrefix)
+// Keys without the expect. prefix are ignored; the result IsZero() when none are set
+func ParseExpectations(values map[string]string) (Expectations, error) {
+	var expectations Expectations
+
+	// Sort keys so that the order of expectations is stable
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := values[key]
+		switch {
+		case key == ExpectObservedGenerationKey:
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return Expectations{}, fmt.Errorf("invalid value for %s: %q (expected true or false)", key, value)
+			}
+			expectations.ObservedGeneration = enabled
+		case strings.HasPrefix(key, ExpectConditionPrefix):
+			conditionType := strings.TrimPrefix(key, ExpectConditionPrefix)
+			status, reason, _ := strings.Cut(value, "/")
+			if conditionType == "" || status == "" {
+				return Expectations{}, fmt.Errorf("invalid condition expectation %s=%s (expected %s<Type>=<Status>[/<Reason>])",
+					key, value, ExpectConditionPrefix)
+			}
+			expectations.Conditions = append(expectations.Conditions, ConditionExpectation{
+				Type:   conditionType,
+				Status: status,
+				Reason: reason,
+			})
+		}
+	}
+
+	return expectations, nil
+}
+
+// observedGeneration returns status.observedGeneration, falling back to the highest
+// observedGeneration reported by the status conditions
+func observedGeneration(obj *unstructured.Unstructured) (int64, bool) {
+	if observed, found, err := unstructured.NestedInt64(obj.Object, "status", "observedGeneration"); found && err == nil {
+		return observed, true
+	}
+
+	conditions, found, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if !found || err != nil {
+		return 0, false
+	}
+	var highest int64
+	reported := false
+	for _, cond := range conditions {
+		condMap, ok := cond.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if observed, ok := condMap["observedGeneration"].(int64); ok {
+			reported = true
+			if observed > highest {
+				highest = observed
+			}
+		}
+	}
+	return highest, reported
+}
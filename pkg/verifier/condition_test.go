@@ -0,0 +1,97 @@
+package verifier
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// securesignWithStatus returns a Securesign at generation 2 with the given conditions
+func securesignWithStatus(conditions ...map[string]interface{}) *unstructured.Unstructured {
+	obj := newSecuresign("ns", "sample", conditions...)
+	obj.SetGeneration(2)
+	return obj
+}
+
+var _ = Describe("Expectations", func() {
+	ready := map[string]interface{}{"type": "Ready", "status": "True", "reason": "Ready"}
+	fulcio := map[string]interface{}{"type": "FulcioAvailable", "status": "True", "reason": "Ready"}
+	failed := map[string]interface{}{"type": "Ready", "status": "False", "reason": "Failure"}
+
+	It("should expect Ready=True by default", func() {
+		expectations := DefaultExpectations()
+		Expect(expectations.Unmet(securesignWithStatus(ready))).To(BeEmpty())
+		Expect(expectations.Unmet(securesignWithStatus(failed))).To(ConsistOf("Ready=True: got Ready=False/Failure"))
+		Expect(expectations.Unmet(nil)).To(ConsistOf("resource not found"))
+	})
+
+	It("should check multiple conditions and reasons", func() {
+		expectations := Expectations{Conditions: []ConditionExpectation{
+			{Type: "Ready", Status: "True"},
+			{Type: "FulcioAvailable", Status: "True", Reason: "Ready"},
+			{Type: "TrillianAvailable", Status: "True"},
+		}}
+		Expect(expectations.Unmet(securesignWithStatus(ready, fulcio))).To(ConsistOf("TrillianAvailable=True: condition not present"))
+	})
+
+	It("should support negative expectations", func() {
+		expectations := Expectations{Conditions: []ConditionExpectation{{Type: "Ready", Status: "False", Reason: "Failure"}}}
+		met, err := expectations.Condition()(securesignWithStatus(failed))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(met).To(BeTrue())
+
+		met, err = expectations.Condition()(securesignWithStatus(ready))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(met).To(BeFalse())
+	})
+
+	It("should compare observedGeneration with metadata.generation", func() {
+		expectations := Expectations{ObservedGeneration: true}
+
+		obj := securesignWithStatus(ready)
+		Expect(expectations.Unmet(obj)).To(ConsistOf("observedGeneration: not reported"))
+
+		Expect(unstructured.SetNestedField(obj.Object, int64(1), "status", "observedGeneration")).To(Succeed())
+		Expect(expectations.Unmet(obj)).To(ConsistOf("observedGeneration: 1 < generation 2"))
+
+		Expect(unstructured.SetNestedField(obj.Object, int64(2), "status", "observedGeneration")).To(Succeed())
+		Expect(expectations.Unmet(obj)).To(BeEmpty())
+	})
+
+	It("should fall back to observedGeneration of the conditions", func() {
+		withGeneration := map[string]interface{}{"type": "Ready", "status": "True", "observedGeneration": int64(2)}
+		expectations := Expectations{ObservedGeneration: true}
+		Expect(expectations.Unmet(securesignWithStatus(withGeneration))).To(BeEmpty())
+	})
+})
+
+var _ = Describe("ParseExpectations", func() {
+	It("should parse expectations from conf values", func() {
+		expectations, err := ParseExpectations(map[string]string{
+			"Issuer":                           "https://issuer.example.com",
+			"expect.condition.Ready":           "False/Failure",
+			"expect.condition.FulcioAvailable": "True",
+			"expect.observedGeneration":        "true",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(expectations.Conditions).To(Equal([]ConditionExpectation{
+			{Type: "FulcioAvailable", Status: "True"},
+			{Type: "Ready", Status: "False", Reason: "Failure"},
+		}))
+		Expect(expectations.ObservedGeneration).To(BeTrue())
+		Expect(expectations.String()).To(Equal("FulcioAvailable=True, Ready=False/Failure, observedGeneration"))
+	})
+
+	It("should return zero expectations when none are set", func() {
+		expectations, err := ParseExpectations(map[string]string{"Issuer": "https://issuer.example.com"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(expectations.IsZero()).To(BeTrue())
+	})
+
+	It("should reject invalid values", func() {
+		_, err := ParseExpectations(map[string]string{"expect.condition.Ready": ""})
+		Expect(err).To(HaveOccurred())
+		_, err = ParseExpectations(map[string]string{"expect.observedGeneration": "maybe"})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,166 @@
+package verifier
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SpecMismatch is a field of the rendered spec that the live object does not reflect
+type SpecMismatch struct {
+	// Path is the field path, e.g. "spec.ctlog.monitoring.enabled" or "spec.tuf.keys[0].name"
+	Path     string
+	Expected interface{}
+	// Actual is nil if the field was dropped
+	Actual interface{}
+	// Missing is true if the field does not exist in the live object
+	Missing bool
+}
+
+// String returns "path: expected X, got Y" or "path: expected X, field missing"
+func (m SpecMismatch) String() string {
+	if m.Missing {
+		return fmt.Sprintf("%s: expected %v, field missing", m.Path, m.Expected)
+	}
+	return fmt.Sprintf("%s: expected %v, got %v", m.Path, m.Expected, m.Actual)
+}
+
+// ConformanceResult is the result of comparing a rendered document with the live object
+type ConformanceResult struct {
+	Ref        Ref
+	Mismatches []SpecMismatch
+	// Err is set if the live object could not be retrieved
+	Err error
+}
+
+// CompareSpec compares the spec of a rendered document with the spec of the live object
+// Fields only present in the live object (operator defaults) are allowed, as are additional list elements;
+// every rendered field that differs or was dropped is reported. Numbers are compared by value
+func CompareSpec(rendered, live *unstructured.Unstructured) []SpecMismatch {
+	renderedSpec, found := rendered.Object["spec"]
+	if !found {
+		return nil
+	}
+	liveSpec, found := live.Object["spec"]
+	if !found {
+		return []SpecMismatch{{Path: "spec", Expected: renderedSpec, Missing: true}}
+	}
+
+	var mismatches []SpecMismatch
+	compareValues("spec", renderedSpec, liveSpec, &mismatches)
+	return mismatches
+}
+
+// CheckConformance compares every rendered document with its live object
+// Documents without namespace are looked up in namespace; cluster-scoped documents should pass ""
+func CheckConformance(ctx context.Context, cli client.Client, namespace string, rendered []*unstructured.Unstructured) []ConformanceResult {
+	results := make([]ConformanceResult, 0, len(rendered))
+	for _, obj := range rendered {
+		ref := Ref{GVK: obj.GroupVersionKind(), Namespace: obj.GetNamespace(), Name: obj.GetName()}
+		if ref.Namespace == "" {
+			ref.Namespace = namespace
+		}
+
+		result := ConformanceResult{Ref: ref}
+		live, err := Get(ctx, cli, ref.Namespace, ref.Name, ref.GVK)
+		if err != nil {
+			result.Err = err
+		} else {
+			result.Mismatches = CompareSpec(obj, live)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// VerifyConformance checks that the live objects still reflect the rendered documents
+// and fails the current spec listing every path-level mismatch
+func VerifyConformance(ctx context.Context, cli client.Client, namespace string, rendered []*unstructured.Unstructured) {
+	var failed []string
+	for _, result := range CheckConformance(ctx, cli, namespace, rendered) {
+		if result.Err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", result.Ref, result.Err))
+		}
+		for _, mismatch := range result.Mismatches {
+			failed = append(failed, fmt.Sprintf("%s: %s", result.Ref, mismatch))
+		}
+	}
+	Expect(failed).To(BeEmpty(), "live objects do not match the rendered config:\n%s", strings.Join(failed, "\n"))
+}
+
+// compareValues walks rendered and records every difference to live below path
+func compareValues(path string, rendered, live interface{}, mismatches *[]SpecMismatch) {
+	switch r := rendered.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			*mismatches = append(*mismatches, SpecMismatch{Path: path, Expected: rendered, Actual: live})
+			return
+		}
+		// Sort keys so that mismatches are reported in a stable order
+		keys := make([]string, 0, len(r))
+		for key := range r {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			childPath := path + "." + key
+			liveValue, exists := l[key]
+			if !exists {
+				*mismatches = append(*mismatches, SpecMismatch{Path: childPath, Expected: r[key], Missing: true})
+				continue
+			}
+			compareValues(childPath, r[key], liveValue, mismatches)
+		}
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok {
+			*mismatches = append(*mismatches, SpecMismatch{Path: path, Expected: rendered, Actual: live})
+			return
+		}
+		for i := range r {
+			childPath := fmt.Sprintf("%s[%d]", path, i)
+			if i >= len(l) {
+				*mismatches = append(*mismatches, SpecMismatch{Path: childPath, Expected: r[i], Missing: true})
+				continue
+			}
+			compareValues(childPath, r[i], l[i], mismatches)
+		}
+	default:
+		if !scalarEqual(rendered, live) {
+			*mismatches = append(*mismatches, SpecMismatch{Path: path, Expected: rendered, Actual: live})
+		}
+	}
+}
+
+// scalarEqual compares two scalars, numbers of different types are compared by value
+func scalarEqual(a, b interface{}) bool {
+	if af, ok := toFloat(a); ok {
+		bf, ok := toFloat(b)
+		return ok && af == bf
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,110 @@
+package verifier
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/petrpinkas/config-examples/pkg/apis/rhtas/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+)
+
+// fromYAML parses a single YAML document
+func fromYAML(doc string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	Expect(yaml.Unmarshal([]byte(doc), &obj.Object)).To(Succeed())
+	return obj
+}
+
+// typedFromYAML parses a YAML document into its typed RHTAS object
+func typedFromYAML(doc string) v1alpha1.Object {
+	obj, err := v1alpha1.Decode([]byte(doc))
+	Expect(err).NotTo(HaveOccurred())
+	return obj
+}
+
+const renderedSpec = `
+apiVersion: rhtas.redhat.com/v1alpha1
+kind: Securesign
+metadata:
+  name: sample
+  namespace: ns
+spec:
+  ctlog:
+    monitoring:
+      enabled: false
+  trillian:
+    server:
+      replicas: 1
+  tuf:
+    keys:
+      - name: rekor.pub
+      - name: ctfe.pub
+`
+
+var _ = Describe("Conformance", func() {
+	It("should allow operator defaults", func() {
+		live := fromYAML(`
+spec:
+  ctlog:
+    monitoring:
+      enabled: false
+    port: 6962
+  trillian:
+    server:
+      replicas: 1.0
+  tuf:
+    keys:
+      - name: rekor.pub
+        secretRef: {name: rekor-pub, key: public}
+      - name: ctfe.pub
+      - name: tsa.certchain.pem
+`)
+		Expect(CompareSpec(fromYAML(renderedSpec), live)).To(BeEmpty())
+	})
+
+	It("should report changed and dropped fields by path", func() {
+		live := fromYAML(`
+spec:
+  ctlog:
+    monitoring:
+      enabled: true
+  trillian: {}
+  tuf:
+    keys:
+      - name: rekor.pub
+`)
+		mismatches := CompareSpec(fromYAML(renderedSpec), live)
+		Expect(mismatches).To(Equal([]SpecMismatch{
+			{Path: "spec.ctlog.monitoring.enabled", Expected: false, Actual: true},
+			{Path: "spec.trillian.server", Expected: map[string]interface{}{"replicas": float64(1)}, Missing: true},
+			{Path: "spec.tuf.keys[1]", Expected: map[string]interface{}{"name": "ctfe.pub"}, Missing: true},
+		}))
+		Expect(mismatches[0].String()).To(Equal("spec.ctlog.monitoring.enabled: expected false, got true"))
+		Expect(mismatches[1].String()).To(Equal("spec.trillian.server: expected map[replicas:1], field missing"))
+	})
+
+	It("should report a missing spec", func() {
+		Expect(CompareSpec(fromYAML(renderedSpec), fromYAML("kind: Securesign"))).To(HaveLen(1))
+		Expect(CompareSpec(fromYAML("kind: ConfigMap"), fromYAML("kind: ConfigMap"))).To(BeEmpty())
+	})
+
+	It("should check live objects and fail the spec listing mismatches", func(ctx SpecContext) {
+		live := fromYAML(renderedSpec)
+		Expect(unstructured.SetNestedField(live.Object, true, "spec", "ctlog", "monitoring", "enabled")).To(Succeed())
+		missing := fromYAML("apiVersion: rhtas.redhat.com/v1alpha1\nkind: Fulcio\nmetadata:\n  name: fulcio-sample\n")
+		cli := fake.NewClientBuilder().WithObjects(live).Build()
+
+		results := CheckConformance(ctx, cli, "ns", []*unstructured.Unstructured{fromYAML(renderedSpec), missing})
+		Expect(results).To(HaveLen(2))
+		Expect(results[0].Mismatches).To(HaveLen(1))
+		Expect(results[1].Ref.Namespace).To(Equal("ns"))
+		Expect(results[1].Err).To(MatchError(ErrNotFound))
+
+		failures := InterceptGomegaFailures(func() {
+			VerifyConformance(ctx, cli, "ns", []*unstructured.Unstructured{fromYAML(renderedSpec)})
+		})
+		Expect(failures).To(HaveLen(1))
+		Expect(failures[0]).To(ContainSubstring("Securesign ns/sample: spec.ctlog.monitoring.enabled: expected false, got true"))
+	})
+})
@@ -0,0 +1,42 @@
+package verifier
+
+import (
+	"context"
+
+	"github.com/petrpinkas/config-examples/pkg/apis/rhtas/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var ctlogGVK = v1alpha1.CTlogGVK
+
+// CTlog is the certificate transparency log for certificates issued by Fulcio
+var CTlog = Component{Name: "ctlog", GVK: ctlogGVK, SpecField: "ctlog"}
+
+// VerifyCTlog waits for the CTlog CR and the workloads it owns to be ready
+func VerifyCTlog(ctx context.Context, cli client.Client, namespace, name string) {
+	VerifyComponents(ctx, cli, namespace, name, ctlogGVK)
+}
+
+// ctlogSecrets returns the CTlog key pair and the root certificates it accepts
+func ctlogSecrets(obj *unstructured.Unstructured) []SecretCheck {
+	checks := []SecretCheck{
+		lookupSecretRef(obj, "CTlog private key", "privateKeyRef"),
+		lookupSecretRef(obj, "CTlog public key", "publicKeyRef"),
+	}
+
+	roots, _, _ := unstructured.NestedSlice(obj.Object, "status", "rootCertificates")
+	if len(roots) == 0 {
+		roots, _, _ = unstructured.NestedSlice(obj.Object, "spec", "rootCertificates")
+	}
+	for _, root := range roots {
+		rootMap, ok := root.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if ref, ok := secretRefAt(rootMap); ok {
+			checks = append(checks, SecretCheck{Description: "CTlog root certificate", Ref: ref})
+		}
+	}
+	return checks
+}
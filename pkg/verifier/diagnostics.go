@@ -0,0 +1,344 @@
+package verifier
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// Default diagnostics settings
+const (
+	DefaultTailLines          = 200
+	DefaultDiagnosticsTimeout = 2 * time.Minute
+)
+
+// DiagnosticsOptions configures the bundle collected when verification fails
+type DiagnosticsOptions struct {
+	// Dir is the artifacts directory, every bundle is written to its own subdirectory
+	Dir string
+	// TailLines is the number of log lines collected per container, DefaultTailLines if zero
+	TailLines int64
+	// OperatorNamespace is the namespace of the RHTAS operator, operator logs are skipped if empty
+	OperatorNamespace string
+	// OperatorSelector selects the operator pods, all pods of OperatorNamespace if empty
+	OperatorSelector client.MatchingLabels
+	// Logs reads container logs, logs are skipped if nil
+	Logs corev1client.PodsGetter
+	// Timeout bounds the collection, DefaultDiagnosticsTimeout if zero
+	Timeout time.Duration
+}
+
+// Diagnostics describes a collected bundle
+type Diagnostics struct {
+	// Dir is the directory the bundle was written to
+	Dir       string
+	Resources int
+	Events    int
+	// Warnings holds the most recent Warning events, newest first
+	Warnings []string
+	Pods     int
+	// NotReadyPods describes the pods that are not ready, e.g. "fulcio-server-xyz (CrashLoopBackOff)"
+	NotReadyPods []string
+	Logs         int
+	// Errors are the parts of the bundle that could not be collected
+	Errors []error
+}
+
+var (
+	diagnosticsMu      sync.Mutex
+	diagnosticsOptions *DiagnosticsOptions
+)
+
+// SetDiagnostics enables diagnostics bundles for failing Verify* waits, nil disables them
+func SetDiagnostics(opts *DiagnosticsOptions) {
+	diagnosticsMu.Lock()
+	defer diagnosticsMu.Unlock()
+	diagnosticsOptions = opts
+}
+
+// Summary returns a short human readable description of the bundle
+func (d *Diagnostics) Summary() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Diagnostics written to %s\n", d.Dir)
+	fmt.Fprintf(&sb, "%d resource(s), %d event(s), %d pod(s), %d log file(s)\n", d.Resources, d.Events, d.Pods, d.Logs)
+	if len(d.NotReadyPods) > 0 {
+		fmt.Fprintf(&sb, "Pods not ready: %s\n", strings.Join(d.NotReadyPods, ", "))
+	}
+	for i, warning := range d.Warnings {
+		if i == 5 {
+			fmt.Fprintf(&sb, "... %d more warning(s), see events.txt\n", len(d.Warnings)-i)
+			break
+		}
+		fmt.Fprintf(&sb, "Warning: %s\n", warning)
+	}
+	for _, err := range d.Errors {
+		fmt.Fprintf(&sb, "Not collected: %v\n", err)
+	}
+	return sb.String()
+}
+
+// CollectDiagnostics writes a diagnostics bundle for a resource and its namespace:
+//   - resources.yaml: the resource and its RHTAS components, including status
+//   - events.txt: the namespace events sorted by time
+//   - pods.txt: pod phases, readiness, restarts and container states
+//   - logs/: the last TailLines of every container, plus the previous container for restarted ones
+//   - operator/: logs of the operator pods
+//
+// Collection is best effort; parts that cannot be collected are listed in Diagnostics.Errors
+func CollectDiagnostics(ctx context.Context, cli client.Client, ref Ref, opts DiagnosticsOptions) (*Diagnostics, error) {
+	if opts.TailLines <= 0 {
+		opts.TailLines = DefaultTailLines
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultDiagnosticsTimeout
+	}
+	// Collect even if the spec context was cancelled, e.g. by a spec timeout
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), opts.Timeout)
+	defer cancel()
+
+	dir := filepath.Join(opts.Dir, fmt.Sprintf("%s-%s-%s", ref.Namespace, ref.Name, time.Now().Format("20060102-150405")))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create diagnostics directory: %w", err)
+	}
+
+	d := &Diagnostics{Dir: dir}
+	d.collectResources(ctx, cli, ref)
+	d.collectEvents(ctx, cli, ref.Namespace)
+	pods := d.collectPods(ctx, cli, ref.Namespace)
+	if opts.Logs != nil {
+		d.collectLogs(ctx, opts.Logs, pods, "logs", opts.TailLines)
+		if opts.OperatorNamespace != "" {
+			operatorPods := &corev1.PodList{}
+			if err := cli.List(ctx, operatorPods, client.InNamespace(opts.OperatorNamespace), opts.OperatorSelector); err != nil {
+				d.addError("operator pods in %s: %w", opts.OperatorNamespace, err)
+			} else {
+				d.collectLogs(ctx, opts.Logs, operatorPods.Items, "operator", opts.TailLines)
+			}
+		}
+	}
+
+	if len(d.Errors) > 0 {
+		var lines []string
+		for _, err := range d.Errors {
+			lines = append(lines, err.Error())
+		}
+		d.write("errors.txt", []byte(strings.Join(lines, "\n")+"\n"))
+	}
+	return d, nil
+}
+
+// collectOnFailure collects and reports a diagnostics bundle if diagnostics are enabled
+// The summary is added to the Ginkgo report and printed to GinkgoWriter
+func collectOnFailure(ctx context.Context, cli client.Client, ref Ref) {
+	diagnosticsMu.Lock()
+	opts := diagnosticsOptions
+	diagnosticsMu.Unlock()
+	if opts == nil {
+		return
+	}
+
+	d, err := CollectDiagnostics(ctx, cli, ref, *opts)
+	if err != nil {
+		GinkgoWriter.Printf("Failed to collect diagnostics for %s: %v\n", ref, err)
+		return
+	}
+	summary := d.Summary()
+	GinkgoWriter.Print(summary)
+	AddReportEntry("Diagnostics "+ref.String(), summary)
+}
+
+// collectResources writes the resource and its components as YAML
+func (d *Diagnostics) collectResources(ctx context.Context, cli client.Client, ref Ref) {
+	objects := []*unstructured.Unstructured{}
+	root, err := Get(ctx, cli, ref.Namespace, ref.Name, ref.GVK)
+	if err != nil {
+		d.addError("resource: %w", err)
+		return
+	}
+	objects = append(objects, root)
+
+	if ref.GVK.GroupKind() == securesignGVK.GroupKind() {
+		components, err := discoverComponents(ctx, cli, ref)
+		if err != nil {
+			d.addError("components: %w", err)
+		}
+		for _, c := range components {
+			if c.obj != nil {
+				objects = append(objects, c.obj)
+			}
+		}
+	}
+
+	var docs []string
+	for _, obj := range objects {
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			d.addError("%s %s: %w", obj.GetKind(), obj.GetName(), err)
+			continue
+		}
+		docs = append(docs, string(data))
+	}
+	d.Resources = len(docs)
+	d.write("resources.yaml", []byte(strings.Join(docs, "---\n")))
+}
+
+// collectEvents writes the namespace events sorted by time
+func (d *Diagnostics) collectEvents(ctx context.Context, cli client.Client, namespace string) {
+	events := &corev1.EventList{}
+	if err := cli.List(ctx, events, client.InNamespace(namespace)); err != nil {
+		d.addError("events: %w", err)
+		return
+	}
+	sort.SliceStable(events.Items, func(i, j int) bool {
+		return eventTime(events.Items[i]).Before(eventTime(events.Items[j]))
+	})
+
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tTYPE\tREASON\tOBJECT\tMESSAGE")
+	for _, e := range events.Items {
+		object := fmt.Sprintf("%s/%s", e.InvolvedObject.Kind, e.InvolvedObject.Name)
+		message := strings.ReplaceAll(e.Message, "\n", " ")
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", eventTime(e).Format(time.RFC3339), e.Type, e.Reason, object, message)
+		if e.Type == corev1.EventTypeWarning {
+			d.Warnings = append([]string{fmt.Sprintf("%s %s: %s", object, e.Reason, message)}, d.Warnings...)
+		}
+	}
+	_ = w.Flush()
+	d.Events = len(events.Items)
+	d.write("events.txt", []byte(sb.String()))
+}
+
+// collectPods writes the pod statuses and returns the pods
+func (d *Diagnostics) collectPods(ctx context.Context, cli client.Client, namespace string) []corev1.Pod {
+	pods := &corev1.PodList{}
+	if err := cli.List(ctx, pods, client.InNamespace(namespace)); err != nil {
+		d.addError("pods: %w", err)
+		return nil
+	}
+
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "POD\tPHASE\tREADY\tRESTARTS\tCONTAINERS")
+	for _, pod := range pods.Items {
+		ready, restarts, states := podState(pod)
+		fmt.Fprintf(w, "%s\t%s\t%t\t%d\t%s\n", pod.Name, pod.Status.Phase, ready, restarts, strings.Join(states, ", "))
+		if !ready && pod.Status.Phase != corev1.PodSucceeded {
+			d.NotReadyPods = append(d.NotReadyPods, fmt.Sprintf("%s (%s)", pod.Name, notReadyPodReason(pod, states)))
+		}
+	}
+	_ = w.Flush()
+	d.Pods = len(pods.Items)
+	d.write("pods.txt", []byte(sb.String()))
+	return pods.Items
+}
+
+// collectLogs writes the last lines of every container of pods to subdir
+// Restarted containers also get the logs of the previous instance, which usually holds the crash
+func (d *Diagnostics) collectLogs(ctx context.Context, logs corev1client.PodsGetter, pods []corev1.Pod, subdir string, tailLines int64) {
+	for _, pod := range pods {
+		statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			base := filepath.Join(subdir, pod.Name+"_"+status.Name)
+			// A waiting container has no current logs, e.g. in CrashLoopBackOff or while pulling the image
+			if status.State.Waiting == nil {
+				d.collectLog(ctx, logs, pod, status.Name, false, tailLines, base+".log")
+			}
+			if status.RestartCount > 0 {
+				d.collectLog(ctx, logs, pod, status.Name, true, tailLines, base+".previous.log")
+			}
+		}
+	}
+}
+
+func (d *Diagnostics) collectLog(ctx context.Context, logs corev1client.PodsGetter, pod corev1.Pod, container string, previous bool, tailLines int64, name string) {
+	data, err := logs.Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: container,
+		Previous:  previous,
+		TailLines: &tailLines,
+	}).DoRaw(ctx)
+	if err != nil {
+		d.addError("logs of %s/%s (previous=%t): %w", pod.Name, container, previous, err)
+		return
+	}
+	d.Logs++
+	d.write(name, data)
+}
+
+func (d *Diagnostics) write(name string, data []byte) {
+	path := filepath.Join(d.Dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		d.addError("%s: %w", name, err)
+		return
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		d.addError("%s: %w", name, err)
+	}
+}
+
+func (d *Diagnostics) addError(format string, args ...interface{}) {
+	d.Errors = append(d.Errors, fmt.Errorf(format, args...))
+}
+
+// podState returns whether all containers are ready, the total restarts and a description of each container
+func podState(pod corev1.Pod) (bool, int32, []string) {
+	ready := len(pod.Status.ContainerStatuses) > 0
+	var restarts int32
+	var states []string
+	for _, status := range pod.Status.ContainerStatuses {
+		ready = ready && status.Ready
+		restarts += status.RestartCount
+		state := "running"
+		switch {
+		case status.State.Waiting != nil:
+			state = status.State.Waiting.Reason
+		case status.State.Terminated != nil:
+			state = fmt.Sprintf("terminated %s (exit %d)", status.State.Terminated.Reason, status.State.Terminated.ExitCode)
+		}
+		states = append(states, status.Name+"="+state)
+	}
+	return ready, restarts, states
+}
+
+// notReadyPodReason returns the most useful reason a pod is not ready
+func notReadyPodReason(pod corev1.Pod, states []string) string {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Waiting != nil && status.State.Waiting.Reason != "" {
+			return status.State.Waiting.Reason
+		}
+	}
+	if pod.Status.Reason != "" {
+		return pod.Status.Reason
+	}
+	if len(states) == 0 {
+		return string(pod.Status.Phase)
+	}
+	return strings.Join(states, ", ")
+}
+
+// eventTime returns the time an event was last seen
+func eventTime(e corev1.Event) time.Time {
+	switch {
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case e.Series != nil && !e.Series.LastObservedTime.IsZero():
+		return e.Series.LastObservedTime.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	default:
+		return e.CreationTimestamp.Time
+	}
+}
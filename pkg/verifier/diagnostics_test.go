@@ -0,0 +1,143 @@
+package verifier
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakeclientset "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// event returns an event for a pod seen at the given time
+func event(name, eventType, reason string, at time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Namespace: "ns", Name: name},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "fulcio-server-abc"},
+		Type:           eventType,
+		Reason:         reason,
+		Message:        reason + " message",
+		LastTimestamp:  metav1.NewTime(at),
+	}
+}
+
+// diagnosticsObjects returns a scenario namespace with a crash looping pod and an operator pod
+func diagnosticsObjects() []client.Object {
+	now := time.Now()
+	crashing := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "fulcio-server-abc"},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{{
+			Name:         "fulcio-server",
+			RestartCount: 3,
+			State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		}}},
+	}
+	running := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "trillian-db-xyz"},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{{
+			Name:  "trillian-db",
+			Ready: true,
+			State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+		}}},
+	}
+	operator := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "operators", Name: "rhtas-operator-123", Labels: map[string]string{"app": "rhtas"}},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{{
+			Name:  "manager",
+			Ready: true,
+			State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+		}}},
+	}
+	other := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "operators", Name: "other-operator"}}
+
+	return []client.Object{
+		newSecuresign("ns", "sample", map[string]interface{}{"type": "Ready", "status": "False", "reason": "Creating"}),
+		crashing, running, operator, other,
+		event("second", corev1.EventTypeWarning, "BackOff", now),
+		event("first", corev1.EventTypeNormal, "Pulled", now.Add(-time.Minute)),
+	}
+}
+
+var _ = Describe("Diagnostics", func() {
+	var (
+		dir  string
+		opts DiagnosticsOptions
+		ref  Ref
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		opts = DiagnosticsOptions{
+			Dir:               dir,
+			TailLines:         10,
+			OperatorNamespace: "operators",
+			OperatorSelector:  client.MatchingLabels{"app": "rhtas"},
+			Logs:              fakeclientset.NewClientset().CoreV1(),
+		}
+		ref = Ref{GVK: securesignGVK, Namespace: "ns", Name: "sample"}
+	})
+
+	It("should write a bundle for the namespace", func(ctx SpecContext) {
+		cli := fake.NewClientBuilder().WithObjects(diagnosticsObjects()...).Build()
+
+		d, err := CollectDiagnostics(ctx, cli, ref, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(d.Errors).To(BeEmpty())
+		Expect(d.Resources).To(Equal(1))
+		Expect(d.Events).To(Equal(2))
+		Expect(d.Pods).To(Equal(2))
+		Expect(d.NotReadyPods).To(ConsistOf("fulcio-server-abc (CrashLoopBackOff)"))
+		Expect(d.Warnings).To(ConsistOf("Pod/fulcio-server-abc BackOff: BackOff message"))
+
+		resources, err := os.ReadFile(filepath.Join(d.Dir, "resources.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(resources)).To(ContainSubstring("reason: Creating"))
+
+		events, err := os.ReadFile(filepath.Join(d.Dir, "events.txt"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(events)).To(MatchRegexp(`(?s)Pulled.*BackOff`))
+
+		pods, err := os.ReadFile(filepath.Join(d.Dir, "pods.txt"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(pods)).To(MatchRegexp(`fulcio-server-abc\s+Running\s+false\s+3\s+fulcio-server=CrashLoopBackOff`))
+
+		// A crash looping container only has previous logs
+		Expect(filepath.Join(d.Dir, "logs", "fulcio-server-abc_fulcio-server.previous.log")).To(BeAnExistingFile())
+		Expect(filepath.Join(d.Dir, "logs", "fulcio-server-abc_fulcio-server.log")).NotTo(BeAnExistingFile())
+		Expect(filepath.Join(d.Dir, "logs", "trillian-db-xyz_trillian-db.log")).To(BeAnExistingFile())
+		Expect(filepath.Join(d.Dir, "operator", "rhtas-operator-123_manager.log")).To(BeAnExistingFile())
+		Expect(d.Logs).To(Equal(3))
+
+		Expect(d.Summary()).To(ContainSubstring("Pods not ready: fulcio-server-abc (CrashLoopBackOff)"))
+	})
+
+	It("should record parts that cannot be collected", func(ctx SpecContext) {
+		opts.Logs = nil
+		d, err := CollectDiagnostics(ctx, fake.NewClientBuilder().Build(), ref, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(d.Errors).To(HaveLen(1))
+		Expect(filepath.Join(d.Dir, "errors.txt")).To(BeAnExistingFile())
+		Expect(d.Summary()).To(ContainSubstring("Not collected: resource:"))
+	})
+
+	It("should collect a bundle and add a report entry when a wait fails", func(ctx SpecContext) {
+		SetDiagnostics(&opts)
+		DeferCleanup(func() { SetDiagnostics(nil) })
+		cli := fake.NewClientBuilder().WithObjects(diagnosticsObjects()...).Build()
+
+		failures := InterceptGomegaFailures(func() {
+			VerifyWithOptions(ctx, cli, "ns", "sample", securesignGVK, fastWait(50*time.Millisecond))
+		})
+		Expect(failures).To(HaveLen(1))
+
+		entries := CurrentSpecReport().ReportEntries
+		Expect(entries).To(HaveLen(1))
+		Expect(entries[0].Name).To(Equal("Diagnostics Securesign ns/sample"))
+		Expect(entries[0].StringRepresentation()).To(ContainSubstring("Diagnostics written to " + dir))
+	})
+})
@@ -0,0 +1,166 @@
+package verifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/petrpinkas/config-examples/pkg/api"
+	"github.com/petrpinkas/config-examples/pkg/apis/rhtas/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// tsaTimestampPath is the RFC 3161 endpoint of the TSA, TSA_URL points to it
+const tsaTimestampPath = "/api/v1/timestamp"
+
+// serviceURLs maps the components clients talk to onto the api values of their URLs
+var serviceURLs = []struct {
+	component Component
+	key       string
+	path      string
+}{
+	{Fulcio, api.FulcioURL, ""},
+	{Rekor, api.RekorURL, ""},
+	{TUF, api.TufURL, ""},
+	{TSA, api.TsaURL, tsaTimestampPath},
+}
+
+// ServiceURL is a value found on the cluster
+type ServiceURL struct {
+	// Key is the api value, e.g. SIGSTORE_FULCIO_URL
+	Key   string
+	Value string
+	// Source describes where the value was found, e.g. "Fulcio sample status.url"
+	Source string
+	// Applied is false if an explicitly set value was kept
+	Applied bool
+}
+
+// String returns a one line summary such as "SIGSTORE_FULCIO_URL=https://fulcio.example.com (Fulcio sample status.url)"
+func (s ServiceURL) String() string {
+	summary := fmt.Sprintf("%s=%s (%s)", s.Key, s.Value, s.Source)
+	if !s.Applied {
+		summary += ", kept explicit value " + api.GetValueFor(s.Key)
+	}
+	return summary
+}
+
+// DiscoverServiceURLs finds the Fulcio, Rekor, TUF and TSA URLs and the OIDC issuer of Fulcio in a namespace
+// and sets them as api values, replacing those of a previous discovery; values set explicitly (e.g. in the environment) are never overridden
+// The components of the Securesign name are used; with an empty name the only Securesign of the namespace,
+// or the component CRs of the namespace if there is no Securesign
+// URLs are taken from status.url, falling back to an owned Route and then an owned Ingress
+func DiscoverServiceURLs(ctx context.Context, cli client.Client, namespace, name string) ([]ServiceURL, error) {
+	// Values of a previous discovery must not leak into this one, e.g. a TSA URL into a stack without a TSA
+	api.ResetDiscovered()
+	crs, err := serviceCRs(ctx, cli, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []ServiceURL
+	for _, service := range serviceURLs {
+		obj := crs[service.component.Name]
+		if obj == nil {
+			continue
+		}
+		url, source, err := resolveURL(ctx, cli, obj)
+		if err != nil {
+			return nil, err
+		}
+		if url == "" {
+			continue
+		}
+		found = append(found, ServiceURL{
+			Key:    service.key,
+			Value:  strings.TrimSuffix(url, "/") + service.path,
+			Source: fmt.Sprintf("%s %s %s", obj.GetKind(), obj.GetName(), source),
+		})
+	}
+	if fulcio := crs[Fulcio.Name]; fulcio != nil {
+		if issuer := oidcIssuer(fulcio); issuer != "" {
+			found = append(found, ServiceURL{
+				Key:    api.OidcIssuerURL,
+				Value:  issuer,
+				Source: fmt.Sprintf("%s %s spec.config.OIDCIssuers", fulcio.GetKind(), fulcio.GetName()),
+			})
+		}
+	}
+
+	for i := range found {
+		found[i].Applied = api.SetDiscovered(found[i].Key, found[i].Value)
+	}
+	return found, nil
+}
+
+// serviceCRs returns the component CRs to take the URLs from by component name
+func serviceCRs(ctx context.Context, cli client.Client, namespace, name string) (map[string]*unstructured.Unstructured, error) {
+	if name == "" {
+		securesigns, err := list(ctx, cli, securesignGVK, namespace)
+		if err != nil && !errors.Is(err, ErrNoKindMatch) {
+			return nil, err
+		}
+		switch len(securesigns) {
+		case 0:
+			return standaloneCRs(ctx, cli, namespace)
+		case 1:
+			name = securesigns[0].GetName()
+		default:
+			return nil, fmt.Errorf("found %d Securesigns in namespace %s, a name is required", len(securesigns), namespace)
+		}
+	}
+
+	components, err := discoverComponents(ctx, cli, Ref{GVK: securesignGVK, Namespace: namespace, Name: name})
+	if err != nil {
+		return nil, err
+	}
+	crs := map[string]*unstructured.Unstructured{}
+	for _, d := range components {
+		if d.obj != nil {
+			crs[d.component.Name] = d.obj
+		}
+	}
+	return crs, nil
+}
+
+// standaloneCRs returns the first CR of every component with a URL in the namespace
+func standaloneCRs(ctx context.Context, cli client.Client, namespace string) (map[string]*unstructured.Unstructured, error) {
+	crs := map[string]*unstructured.Unstructured{}
+	for _, service := range serviceURLs {
+		items, err := list(ctx, cli, service.component.GVK, namespace)
+		if err != nil && !errors.Is(err, ErrNoKindMatch) {
+			return nil, err
+		}
+		if len(items) > 0 {
+			crs[service.component.Name] = &items[0]
+		}
+	}
+	if len(crs) == 0 {
+		return nil, fmt.Errorf("no Securesign or component CRs found in namespace %s", namespace)
+	}
+	return crs, nil
+}
+
+// oidcIssuer returns the first OIDC issuer configured for Fulcio
+// The conversion is lenient, so values of the wrong type elsewhere in the spec do not hide the issuer
+func oidcIssuer(fulcio *unstructured.Unstructured) string {
+	obj, _, err := v1alpha1.FromUnstructuredLenient(fulcio)
+	if err != nil {
+		return ""
+	}
+	typed, ok := obj.(*v1alpha1.Fulcio)
+	if !ok || typed.Spec.Config == nil {
+		return ""
+	}
+	for _, issuer := range typed.Spec.Config.OIDCIssuers {
+		if issuer.Issuer != "" {
+			return issuer.Issuer
+		}
+		if issuer.IssuerURL != "" {
+			return issuer.IssuerURL
+		}
+	}
+	return ""
+}
@@ -0,0 +1,132 @@
+package verifier
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/petrpinkas/config-examples/pkg/api"
+	"github.com/spf13/viper"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// discoveredValues returns the discovered values by key
+func discoveredValues(found []ServiceURL) map[string]string {
+	values := map[string]string{}
+	for _, s := range found {
+		values[s.Key] = s.Value
+	}
+	return values
+}
+
+var _ = Describe("DiscoverServiceURLs", func() {
+	var (
+		securesign *unstructured.Unstructured
+		objects    []client.Object
+	)
+
+	BeforeEach(func() {
+		previous := api.Values
+		api.Values = viper.New()
+		DeferCleanup(func() { api.Values = previous })
+
+		securesign = newSecuresign("ns", "sample")
+		securesign.SetUID("securesign-uid")
+		fulcio := owned(fulcioGVK, "sample", securesign)
+		setField(fulcio, "https://fulcio.example.com", "status", "url")
+		setField(fulcio, []interface{}{map[string]interface{}{
+			"ClientID": "trusted-artifact-signer", "Issuer": "https://keycloak.example.com/auth/realms/trusted-artifact-signer",
+		}}, "spec", "config", "OIDCIssuers")
+		rekor := owned(rekorGVK, "sample", securesign)
+		route := owned(routeGVK, "rekor-server", rekor)
+		setField(route, "rekor.example.com", "spec", "host")
+		setField(route, map[string]interface{}{"termination": "edge"}, "spec", "tls")
+		tuf := owned(tufGVK, "sample", securesign)
+		setField(tuf, "https://tuf.example.com/", "status", "url")
+		tsa := owned(tsaGVK, "sample", securesign)
+		setField(tsa, "https://tsa.example.com", "status", "url")
+		objects = []client.Object{securesign, fulcio, rekor, route, tuf, tsa}
+	})
+
+	It("should set the URLs of the only Securesign in the namespace", func(ctx SpecContext) {
+		cli := fake.NewClientBuilder().WithObjects(objects...).Build()
+
+		found, err := DiscoverServiceURLs(ctx, cli, "ns", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(discoveredValues(found)).To(Equal(map[string]string{
+			api.FulcioURL:     "https://fulcio.example.com",
+			api.RekorURL:      "https://rekor.example.com",
+			api.TufURL:        "https://tuf.example.com",
+			api.TsaURL:        "https://tsa.example.com/api/v1/timestamp",
+			api.OidcIssuerURL: "https://keycloak.example.com/auth/realms/trusted-artifact-signer",
+		}))
+		Expect(found[1].String()).To(Equal("SIGSTORE_REKOR_URL=https://rekor.example.com (Rekor sample Route rekor-server)"))
+		Expect(api.GetValueFor(api.TsaURL)).To(Equal("https://tsa.example.com/api/v1/timestamp"))
+	})
+
+	It("should never override explicitly set values", func(ctx SpecContext) {
+		api.Values.Set(api.FulcioURL, "https://fulcio.local")
+		cli := fake.NewClientBuilder().WithObjects(objects...).Build()
+
+		found, err := DiscoverServiceURLs(ctx, cli, "ns", "sample")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found[0].Applied).To(BeFalse())
+		Expect(found[0].String()).To(HaveSuffix("kept explicit value https://fulcio.local"))
+		Expect(found[1].Applied).To(BeTrue())
+		Expect(api.GetValueFor(api.FulcioURL)).To(Equal("https://fulcio.local"))
+		Expect(api.GetValueFor(api.RekorURL)).To(Equal("https://rekor.example.com"))
+	})
+
+	It("should find the OIDC issuer of a Fulcio with unexpected fields", func(ctx SpecContext) {
+		fulcio := objects[1].(*unstructured.Unstructured)
+		setField(fulcio, "yes", "spec", "externalAccess", "enabled")
+		cli := fake.NewClientBuilder().WithObjects(objects...).Build()
+
+		found, err := DiscoverServiceURLs(ctx, cli, "ns", "sample")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(discoveredValues(found)).To(HaveKeyWithValue(api.OidcIssuerURL,
+			"https://keycloak.example.com/auth/realms/trusted-artifact-signer"))
+	})
+
+	It("should use component CRs without a Securesign", func(ctx SpecContext) {
+		tsa := &unstructured.Unstructured{}
+		tsa.SetGroupVersionKind(tsaGVK)
+		tsa.SetNamespace("ns")
+		tsa.SetName("standalone")
+		setField(tsa, "https://tsa.standalone.example.com", "status", "url")
+		cli := fake.NewClientBuilder().WithObjects(tsa).Build()
+
+		found, err := DiscoverServiceURLs(ctx, cli, "ns", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(discoveredValues(found)).To(Equal(map[string]string{
+			api.TsaURL: "https://tsa.standalone.example.com/api/v1/timestamp",
+		}))
+	})
+
+	It("should replace the values of a previous discovery", func(ctx SpecContext) {
+		tsa := &unstructured.Unstructured{}
+		tsa.SetGroupVersionKind(tsaGVK)
+		tsa.SetNamespace("other")
+		tsa.SetName("standalone")
+		setField(tsa, "https://tsa.standalone.example.com", "status", "url")
+		cli := fake.NewClientBuilder().WithObjects(append(objects, tsa)...).Build()
+
+		_, err := DiscoverServiceURLs(ctx, cli, "ns", "sample")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(api.GetValueFor(api.FulcioURL)).To(Equal("https://fulcio.example.com"))
+
+		_, err = DiscoverServiceURLs(ctx, cli, "other", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(api.GetValueFor(api.FulcioURL)).To(BeEmpty())
+		Expect(api.GetValueFor(api.TsaURL)).To(Equal("https://tsa.standalone.example.com/api/v1/timestamp"))
+	})
+
+	It("should require a name for several Securesigns and fail for an empty namespace", func(ctx SpecContext) {
+		cli := fake.NewClientBuilder().WithObjects(append(objects, newSecuresign("ns", "other"))...).Build()
+		_, err := DiscoverServiceURLs(ctx, cli, "ns", "")
+		Expect(err).To(MatchError(ContainSubstring("found 2 Securesigns in namespace ns")))
+
+		_, err = DiscoverServiceURLs(ctx, cli, "empty", "")
+		Expect(err).To(MatchError(ContainSubstring("no Securesign or component CRs found in namespace empty")))
+	})
+})
@@ -0,0 +1,281 @@
+package verifier
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/petrpinkas/config-examples/pkg/clients"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var ingressGVK = schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"}
+
+// Default probe settings
+const (
+	DefaultProbeTimeout = 10 * time.Second
+	// DefaultEndpointTimeout is the time to wait for all endpoints to answer, routes may take a while to be admitted
+	DefaultEndpointTimeout = 5 * time.Minute
+)
+
+// ErrURLNotResolved means no URL was found in the CR status, a Route or an Ingress
+var ErrURLNotResolved = errors.New("URL not resolved")
+
+// Endpoint is the external URL of a component
+type Endpoint struct {
+	Component Component
+	// URL is the base URL, empty if it could not be resolved
+	URL string
+	// Source describes where the URL was found, e.g. "status.url" or "Route fulcio-server"
+	Source string
+}
+
+// ProbeURL returns the URL of the well-known health or API path
+func (e Endpoint) ProbeURL() string {
+	return strings.TrimSuffix(e.URL, "/") + e.Component.HealthPath
+}
+
+// ProbeOptions configures the HTTP probes
+type ProbeOptions struct {
+	// HTTPClient is used for the probes, a client with Timeout and InsecureSkipVerify is created if nil
+	HTTPClient *http.Client
+	// Timeout of a single probe, DefaultProbeTimeout if zero
+	Timeout time.Duration
+	// InsecureSkipVerify disables TLS verification, e.g. for routes with self-signed certificates
+	InsecureSkipVerify bool
+}
+
+// EndpointResult is the result of probing an endpoint
+type EndpointResult struct {
+	Endpoint   Endpoint
+	StatusCode int
+	// Err is nil if the endpoint answered with 2xx and a valid body
+	Err error
+}
+
+// String returns a one line summary such as "fulcio https://fulcio.example.com/api/v2/configuration: 200 OK"
+func (r EndpointResult) String() string {
+	if r.Endpoint.URL == "" {
+		return fmt.Sprintf("%s: %v", r.Endpoint.Component.Name, r.Err)
+	}
+	if r.Err != nil {
+		return fmt.Sprintf("%s %s: %v", r.Endpoint.Component.Name, r.Endpoint.ProbeURL(), r.Err)
+	}
+	return fmt.Sprintf("%s %s: %d %s", r.Endpoint.Component.Name, r.Endpoint.ProbeURL(), r.StatusCode, http.StatusText(r.StatusCode))
+}
+
+// ResolveEndpoints returns the external URLs of the components of a Securesign, or of a single component CR
+// Only components with an HTTP API and spec.externalAccess.enabled are returned
+// The URL is taken from status.url, falling back to an owned Route and then an owned Ingress
+func ResolveEndpoints(ctx context.Context, cli client.Client, ref Ref) ([]Endpoint, error) {
+	components, err := discoverComponents(ctx, cli, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoints []Endpoint
+	for _, d := range components {
+		if d.obj == nil || d.component.HealthPath == "" {
+			continue
+		}
+		if enabled, _, _ := unstructured.NestedBool(d.obj.Object, "spec", "externalAccess", "enabled"); !enabled {
+			continue
+		}
+		endpoint := Endpoint{Component: d.component}
+		endpoint.URL, endpoint.Source, err = resolveURL(ctx, cli, d.obj)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints, nil
+}
+
+// ProbeEndpoints sends a GET request to the well-known path of every endpoint and validates the body:
+// JSON for Fulcio, Rekor and TUF, a PEM certificate chain for the TSA
+func ProbeEndpoints(ctx context.Context, endpoints []Endpoint, opts ProbeOptions) []EndpointResult {
+	httpClient := probeClient(opts)
+	results := make([]EndpointResult, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		result := EndpointResult{Endpoint: endpoint}
+		if endpoint.URL == "" {
+			result.Err = ErrURLNotResolved
+		} else {
+			result.StatusCode, result.Err = probe(ctx, httpClient, endpoint)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// WaitForEndpoints resolves and probes the endpoints until all of them answer
+// On timeout the returned error wraps ErrWaitTimeout and lists the failing endpoints
+func WaitForEndpoints(ctx context.Context, cli client.Client, ref Ref, probeOpts ProbeOptions, opts WaitOptions) ([]EndpointResult, error) {
+	opts = withDefaults(opts)
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	var (
+		// results of the last round that was not cut short by the timeout
+		results []EndpointResult
+		lastErr error
+	)
+	err := pollWithBackoff(ctx, opts, func() (bool, error) {
+		var endpoints []Endpoint
+		endpoints, lastErr = ResolveEndpoints(ctx, cli, ref)
+		if lastErr != nil {
+			if IsRetryable(lastErr) {
+				return false, nil
+			}
+			return false, lastErr
+		}
+		probed := ProbeEndpoints(ctx, endpoints, probeOpts)
+		done := len(failedEndpoints(probed)) == 0
+		if done || ctx.Err() == nil || results == nil {
+			results = probed
+		}
+		return done, nil
+	}, func(error) error {
+		elapsed := time.Since(start).Round(time.Second)
+		if lastErr != nil {
+			return fmt.Errorf("%w after %s waiting for endpoints of %s: %w", ErrWaitTimeout, elapsed, ref, lastErr)
+		}
+		return fmt.Errorf("%w after %s waiting for endpoints of %s:\n%s",
+			ErrWaitTimeout, elapsed, ref, strings.Join(failedEndpoints(results), "\n"))
+	})
+	if lastErr != nil {
+		return nil, err
+	}
+	return results, err
+}
+
+// VerifyEndpoints waits until the external endpoints of a Securesign (or a single component CR) answer
+// and fails the current spec listing the failing endpoints otherwise
+func VerifyEndpoints(ctx context.Context, cli client.Client, namespace, name string, gvk schema.GroupVersionKind, probeOpts ProbeOptions) {
+	opts := DefaultWaitOptions()
+	opts.Timeout = DefaultEndpointTimeout
+	results, err := WaitForEndpoints(ctx, cli, Ref{GVK: gvk, Namespace: namespace, Name: name}, probeOpts, opts)
+	Expect(err).NotTo(HaveOccurred())
+	for _, result := range results {
+		GinkgoWriter.Printf("%s\n", result)
+	}
+}
+
+// resolveURL returns the URL of a component CR and where it was found
+func resolveURL(ctx context.Context, cli client.Client, obj *unstructured.Unstructured) (string, string, error) {
+	if url, _, _ := unstructured.NestedString(obj.Object, "status", "url"); url != "" {
+		return url, "status.url", nil
+	}
+
+	routes, err := list(ctx, cli, routeGVK, obj.GetNamespace())
+	if err != nil && !errors.Is(err, ErrNoKindMatch) {
+		return "", "", err
+	}
+	for i := range routes {
+		host, _, _ := unstructured.NestedString(routes[i].Object, "spec", "host")
+		if !isOwnedBy(&routes[i], obj.GetUID()) || host == "" {
+			continue
+		}
+		scheme := "http"
+		if _, tls, _ := unstructured.NestedMap(routes[i].Object, "spec", "tls"); tls {
+			scheme = "https"
+		}
+		return scheme + "://" + host, "Route " + routes[i].GetName(), nil
+	}
+
+	ingresses, err := list(ctx, cli, ingressGVK, obj.GetNamespace())
+	if err != nil && !errors.Is(err, ErrNoKindMatch) {
+		return "", "", err
+	}
+	for i := range ingresses {
+		if !isOwnedBy(&ingresses[i], obj.GetUID()) {
+			continue
+		}
+		rules, _, _ := unstructured.NestedSlice(ingresses[i].Object, "spec", "rules")
+		for _, rule := range rules {
+			ruleMap, ok := rule.(map[string]interface{})
+			host, _ := ruleMap["host"].(string)
+			if !ok || host == "" {
+				continue
+			}
+			scheme := "http"
+			if tls, _, _ := unstructured.NestedSlice(ingresses[i].Object, "spec", "tls"); len(tls) > 0 {
+				scheme = "https"
+			}
+			return scheme + "://" + host, "Ingress " + ingresses[i].GetName(), nil
+		}
+	}
+	return "", "", nil
+}
+
+// probe requests the health path of an endpoint and validates the response body
+func probe(ctx context.Context, httpClient *http.Client, endpoint Endpoint) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.ProbeURL(), nil)
+	if err != nil {
+		return 0, fmt.Errorf("invalid URL: %w", err)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return resp.StatusCode, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	switch endpoint.Component.Name {
+	case TSA.Name:
+		if _, err := clients.ParseCertificates(body); err != nil {
+			return resp.StatusCode, fmt.Errorf("invalid certificate chain: %w", err)
+		}
+	default:
+		if !json.Valid(body) {
+			return resp.StatusCode, fmt.Errorf("response is not valid JSON")
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// probeClient returns the configured HTTP client or creates one
+func probeClient(opts ProbeOptions) *http.Client {
+	if opts.HTTPClient != nil {
+		return opts.HTTPClient
+	}
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultProbeTimeout
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if opts.InsecureSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec // opt-in for self-signed routes
+	}
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// failedEndpoints describes every result with an error
+func failedEndpoints(results []EndpointResult) []string {
+	var failed []string
+	for _, result := range results {
+		if result.Err != nil {
+			failed = append(failed, result.String())
+		}
+	}
+	return failed
+}
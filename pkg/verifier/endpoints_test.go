@@ -0,0 +1,142 @@
+package verifier
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// rhtasServer returns a stand-in serving the well-known paths of all components
+// TUF answers with an invalid root.json until tufReady is closed
+func rhtasServer(tufReady chan struct{}) *httptest.Server {
+	chain := certificatePEM(CertificateSubject{CommonName: "tsa.hostname-leaf"})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/configuration", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"issuers":[]}`))
+	})
+	mux.HandleFunc("/api/v1/log", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"treeSize":1}`))
+	})
+	mux.HandleFunc("/api/v1/timestamp/certchain", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(chain))
+	})
+	mux.HandleFunc("/root.json", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-tufReady:
+			_, _ = w.Write([]byte(`{"signed":{}}`))
+		default:
+			_, _ = w.Write([]byte(`<html>starting</html>`))
+		}
+	})
+	return httptest.NewServer(mux)
+}
+
+// withExternalAccess enables spec.externalAccess of a component CR
+func withExternalAccess(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	setField(obj, true, "spec", "externalAccess", "enabled")
+	return obj
+}
+
+var _ = Describe("Endpoints", func() {
+	var (
+		server   *httptest.Server
+		tufReady chan struct{}
+		host     string
+		cli      client.Client
+		ref      Ref
+	)
+
+	BeforeEach(func() {
+		tufReady = make(chan struct{})
+		server = rhtasServer(tufReady)
+		DeferCleanup(server.Close)
+		host = strings.TrimPrefix(server.URL, "http://")
+
+		securesign := newSecuresign("ns", "sample")
+		securesign.SetUID("securesign-uid")
+
+		// Fulcio reports its URL in status, Rekor has a Route, the TSA an Ingress
+		fulcio := withExternalAccess(owned(fulcioGVK, "sample", securesign))
+		setField(fulcio, server.URL, "status", "url")
+		rekor := withExternalAccess(owned(rekorGVK, "sample", securesign))
+		route := owned(routeGVK, "rekor-server", rekor)
+		setField(route, host, "spec", "host")
+		tsa := withExternalAccess(owned(tsaGVK, "sample", securesign))
+		ingress := owned(ingressGVK, "tsa-server", tsa)
+		setField(ingress, []interface{}{map[string]interface{}{"host": host}}, "spec", "rules")
+		tuf := withExternalAccess(owned(tufGVK, "sample", securesign))
+		setField(tuf, server.URL+"/", "status", "url")
+		// Components without external access or HTTP API are not probed
+		ctlog := withExternalAccess(owned(ctlogGVK, "sample", securesign))
+		trillian := owned(trillianGVK, "sample", securesign)
+
+		cli = fake.NewClientBuilder().WithObjects(securesign, fulcio, rekor, route, tsa, ingress, tuf, ctlog, trillian).Build()
+		ref = Ref{GVK: securesignGVK, Namespace: "ns", Name: "sample"}
+	})
+
+	It("should resolve URLs from status, routes and ingresses", func(ctx SpecContext) {
+		endpoints, err := ResolveEndpoints(ctx, cli, ref)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(endpoints).To(HaveLen(4))
+
+		sources := map[string]string{}
+		for _, endpoint := range endpoints {
+			sources[endpoint.Component.Name] = endpoint.Source + " " + endpoint.ProbeURL()
+		}
+		Expect(sources).To(Equal(map[string]string{
+			"fulcio": "status.url " + server.URL + "/api/v2/configuration",
+			"rekor":  "Route rekor-server http://" + host + "/api/v1/log",
+			"tsa":    "Ingress tsa-server http://" + host + "/api/v1/timestamp/certchain",
+			"tuf":    "status.url " + server.URL + "/root.json",
+		}))
+	})
+
+	It("should probe the well-known paths and validate the responses", func(ctx SpecContext) {
+		endpoints, err := ResolveEndpoints(ctx, cli, ref)
+		Expect(err).NotTo(HaveOccurred())
+
+		results := ProbeEndpoints(ctx, endpoints, ProbeOptions{HTTPClient: server.Client()})
+		Expect(results).To(HaveLen(4))
+		for _, result := range results {
+			if result.Endpoint.Component == TUF {
+				Expect(result.Err).To(MatchError(ContainSubstring("not valid JSON")))
+				continue
+			}
+			Expect(result.Err).NotTo(HaveOccurred(), result.String())
+			Expect(result.StatusCode).To(Equal(http.StatusOK))
+		}
+	})
+
+	It("should report errors, unresolved URLs and unexpected status codes", func(ctx SpecContext) {
+		results := ProbeEndpoints(ctx, []Endpoint{
+			{Component: Fulcio},
+			{Component: Rekor, URL: server.URL + "/missing"},
+		}, ProbeOptions{})
+		Expect(errors.Is(results[0].Err, ErrURLNotResolved)).To(BeTrue())
+		Expect(results[0].String()).To(Equal("fulcio: URL not resolved"))
+		Expect(results[1].StatusCode).To(Equal(http.StatusNotFound))
+		Expect(results[1].String()).To(Equal("rekor " + server.URL + "/missing/api/v1/log: unexpected status 404 Not Found"))
+	})
+
+	It("should wait until all endpoints answer", func(ctx SpecContext) {
+		time.AfterFunc(50*time.Millisecond, func() { close(tufReady) })
+		results, err := WaitForEndpoints(ctx, cli, ref, ProbeOptions{}, fastWait(5*time.Second))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(HaveLen(4))
+	})
+
+	It("should time out listing the failing endpoints", func(ctx SpecContext) {
+		_, err := WaitForEndpoints(ctx, cli, ref, ProbeOptions{}, fastWait(50*time.Millisecond))
+		Expect(errors.Is(err, ErrWaitTimeout)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("tuf " + server.URL + "/root.json: response is not valid JSON"))
+		Expect(err.Error()).NotTo(ContainSubstring("fulcio"))
+	})
+})
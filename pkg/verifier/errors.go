@@ -0,0 +1,95 @@
+package verifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Reasons for a failed Get, use errors.Is(err, ErrNotFound) to check them
+var (
+	// ErrNotFound means the resource does not exist (yet), retryable
+	ErrNotFound = errors.New("not found")
+	// ErrForbidden means the client is not allowed to read the resource (RBAC), not retryable
+	ErrForbidden = errors.New("forbidden")
+	// ErrUnauthorized means the client credentials were rejected, not retryable
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrNoKindMatch means no CRD is registered for the kind, not retryable
+	ErrNoKindMatch = errors.New("no matches for kind")
+	// ErrTimeout means the API server did not answer in time or is throttling, retryable
+	ErrTimeout = errors.New("timeout")
+	// ErrUnknown is any other API error, retryable
+	ErrUnknown = errors.New("unknown error")
+)
+
+// GetError is returned by Get when a resource cannot be retrieved
+type GetError struct {
+	GVK       schema.GroupVersionKind
+	Namespace string
+	Name      string
+	// Reason is one of the Err* reasons above
+	Reason error
+	// Err is the underlying error returned by the client
+	Err error
+}
+
+// Error returns the reason together with the underlying message
+func (e *GetError) Error() string {
+	return fmt.Sprintf("failed to get %s %s/%s: %v: %v", e.GVK.Kind, e.Namespace, e.Name, e.Reason, e.Err)
+}
+
+// Unwrap returns both the reason and the underlying error so errors.Is works for both
+func (e *GetError) Unwrap() []error {
+	return []error{e.Reason, e.Err}
+}
+
+// Retryable returns true if waiting longer may resolve the error
+func (e *GetError) Retryable() bool {
+	switch e.Reason {
+	case ErrForbidden, ErrUnauthorized, ErrNoKindMatch:
+		return false
+	default:
+		return true
+	}
+}
+
+// IsRetryable returns true if err is nil, not a GetError or a retryable GetError
+func IsRetryable(err error) bool {
+	var getErr *GetError
+	if errors.As(err, &getErr) {
+		return getErr.Retryable()
+	}
+	return true
+}
+
+// newGetError classifies a client error
+func newGetError(gvk schema.GroupVersionKind, namespace, name string, err error) *GetError {
+	var reason error
+	switch {
+	case apierrors.IsNotFound(err):
+		reason = ErrNotFound
+	case apierrors.IsForbidden(err):
+		reason = ErrForbidden
+	case apierrors.IsUnauthorized(err):
+		reason = ErrUnauthorized
+	case meta.IsNoMatchError(err):
+		reason = ErrNoKindMatch
+	case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err), apierrors.IsTooManyRequests(err),
+		errors.Is(err, context.DeadlineExceeded):
+		reason = ErrTimeout
+	default:
+		reason = ErrUnknown
+	}
+
+	return &GetError{
+		GVK:       gvk,
+		Namespace: namespace,
+		Name:      name,
+		Reason:    reason,
+		Err:       err,
+	}
+}
@@ -0,0 +1,27 @@
+package verifier
+
+import (
+	"context"
+
+	"github.com/petrpinkas/config-examples/pkg/apis/rhtas/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var fulcioGVK = v1alpha1.FulcioGVK
+
+// Fulcio issues code signing certificates
+var Fulcio = Component{Name: "fulcio", GVK: fulcioGVK, SpecField: "fulcio", HealthPath: "/api/v2/configuration"}
+
+// VerifyFulcio waits for the Fulcio CR and the workloads it owns to be ready
+func VerifyFulcio(ctx context.Context, cli client.Client, namespace, name string) {
+	VerifyComponents(ctx, cli, namespace, name, fulcioGVK)
+}
+
+// fulcioSecrets returns the Fulcio CA certificate and private key
+func fulcioSecrets(obj *unstructured.Unstructured) []SecretCheck {
+	return []SecretCheck{
+		lookupSecretRef(obj, "Fulcio CA certificate", "certificate", "caRef"),
+		lookupSecretRef(obj, "Fulcio private key", "certificate", "privateKeyRef"),
+	}
+}
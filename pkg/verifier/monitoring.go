@@ -0,0 +1,418 @@
+package verifier
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/petrpinkas/config-examples/pkg/apis/rhtas/v1alpha1"
+	"github.com/prometheus/common/expfmt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	serviceMonitorGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}
+	podMonitorGVK     = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PodMonitor"}
+	podGVK            = schema.GroupVersionKind{Version: "v1", Kind: "Pod"}
+)
+
+// MetricsTarget is a Service or Pod port scraped by a ServiceMonitor or PodMonitor
+type MetricsTarget struct {
+	// Kind is "Service" or "Pod"
+	Kind      string
+	Namespace string
+	Name      string
+	// Port is the Service port name or number, or the Pod port number
+	Port   string
+	Path   string
+	Scheme string
+}
+
+// String returns "Service ns/name:port/path"
+func (t MetricsTarget) String() string {
+	return fmt.Sprintf("%s %s/%s:%s%s", t.Kind, t.Namespace, t.Name, t.Port, t.Path)
+}
+
+// MetricsScraper fetches the metrics exposed by a target
+type MetricsScraper interface {
+	Scrape(ctx context.Context, target MetricsTarget) ([]byte, error)
+}
+
+// ScraperFunc adapts a function to MetricsScraper, e.g. to scrape an in-process stand-in
+type ScraperFunc func(ctx context.Context, target MetricsTarget) ([]byte, error)
+
+// Scrape calls f
+func (f ScraperFunc) Scrape(ctx context.Context, target MetricsTarget) ([]byte, error) {
+	return f(ctx, target)
+}
+
+// ProxyScraper returns a MetricsScraper that reaches targets through the API server proxy,
+// so no port-forward or network access to the pods is needed
+func ProxyScraper(core corev1client.CoreV1Interface) MetricsScraper {
+	return ScraperFunc(func(ctx context.Context, target MetricsTarget) ([]byte, error) {
+		if target.Kind == podGVK.Kind {
+			return core.Pods(target.Namespace).ProxyGet(target.Scheme, target.Name, target.Port, target.Path, nil).DoRaw(ctx)
+		}
+		return core.Services(target.Namespace).ProxyGet(target.Scheme, target.Name, target.Port, target.Path, nil).DoRaw(ctx)
+	})
+}
+
+// MonitoringCheck is the result of checking the monitoring setup of a component
+type MonitoringCheck struct {
+	Component Component
+	// Enabled is the monitoring.enabled value of the rendered config
+	Enabled bool
+	// Monitors lists the ServiceMonitors and PodMonitors owned by the component, e.g. "ServiceMonitor fulcio-metrics"
+	Monitors []string
+	// Targets are the endpoints scraped by the monitors
+	Targets []MetricsTarget
+	// Err is set if monitors exist although monitoring is disabled, or the other way round
+	Err error
+}
+
+// ExpectedMonitoring returns monitoring.enabled per component name as set in a rendered config
+// Components without an explicit setting are not returned, their monitoring is left to operator defaults
+func ExpectedMonitoring(rendered v1alpha1.Object) map[string]bool {
+	expected := map[string]bool{}
+	for name, monitoring := range monitoringConfigs(rendered) {
+		if monitoring != nil {
+			expected[name] = monitoring.Enabled
+		}
+	}
+	return expected
+}
+
+// monitoringConfigs returns the monitoring config per component name of a Securesign or component CR
+func monitoringConfigs(rendered v1alpha1.Object) map[string]*v1alpha1.MonitoringConfig {
+	configs := map[string]*v1alpha1.MonitoringConfig{}
+	switch obj := rendered.(type) {
+	case *v1alpha1.Securesign:
+		spec := obj.Spec
+		if spec.Trillian != nil {
+			configs[Trillian.Name] = spec.Trillian.Monitoring
+		}
+		if spec.Fulcio != nil {
+			configs[Fulcio.Name] = spec.Fulcio.Monitoring
+		}
+		if spec.Rekor != nil {
+			configs[Rekor.Name] = spec.Rekor.Monitoring
+		}
+		if spec.Ctlog != nil {
+			configs[CTlog.Name] = spec.Ctlog.Monitoring
+		}
+		if spec.TimestampAuthority != nil {
+			configs[TSA.Name] = spec.TimestampAuthority.Monitoring
+		}
+	case *v1alpha1.Trillian:
+		configs[Trillian.Name] = obj.Spec.Monitoring
+	case *v1alpha1.Fulcio:
+		configs[Fulcio.Name] = obj.Spec.Monitoring
+	case *v1alpha1.Rekor:
+		configs[Rekor.Name] = obj.Spec.Monitoring
+	case *v1alpha1.CTlog:
+		configs[CTlog.Name] = obj.Spec.Monitoring
+	case *v1alpha1.TimestampAuthority:
+		configs[TSA.Name] = obj.Spec.Monitoring
+	}
+	return configs
+}
+
+// CheckMonitoring checks that every component in expected owns a ServiceMonitor or PodMonitor exactly when
+// monitoring is enabled, and resolves the scrape targets of the monitors
+func CheckMonitoring(ctx context.Context, cli client.Client, ref Ref, expected map[string]bool) ([]MonitoringCheck, error) {
+	components, err := discoverComponents(ctx, cli, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	var checks []MonitoringCheck
+	for _, d := range components {
+		enabled, ok := expected[d.component.Name]
+		if !ok {
+			continue
+		}
+		check := MonitoringCheck{Component: d.component, Enabled: enabled}
+		if d.obj == nil {
+			check.Err = fmt.Errorf("component %s not found", d.component.Name)
+			checks = append(checks, check)
+			continue
+		}
+
+		found, err := findMonitors(ctx, cli, d.obj, &check)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case enabled && !found:
+			check.Err = errors.New("monitoring is enabled but no ServiceMonitor or PodMonitor exists")
+		case !enabled && found:
+			check.Err = fmt.Errorf("monitoring is disabled but %s exist(s)", strings.Join(check.Monitors, ", "))
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+// ScrapeMetrics scrapes a target and validates the Prometheus text format
+// It returns the names of the metric families
+func ScrapeMetrics(ctx context.Context, scraper MetricsScraper, target MetricsTarget) ([]string, error) {
+	data, err := scraper.Scrape(ctx, target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scrape %s: %w", target, err)
+	}
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Prometheus text format from %s: %w", target, err)
+	}
+	if len(families) == 0 {
+		return nil, fmt.Errorf("no metrics from %s", target)
+	}
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// VerifyMonitoring checks the monitors against monitoring.enabled of the rendered config and, if scraper
+// is not nil, scrapes every target of the enabled components; it fails the current spec listing all problems
+func VerifyMonitoring(ctx context.Context, cli client.Client, namespace, name string, gvk schema.GroupVersionKind, rendered v1alpha1.Object, scraper MetricsScraper) {
+	checks, err := CheckMonitoring(ctx, cli, Ref{GVK: gvk, Namespace: namespace, Name: name}, ExpectedMonitoring(rendered))
+	Expect(err).NotTo(HaveOccurred())
+
+	var failed []string
+	for _, check := range checks {
+		if check.Err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", check.Component.Name, check.Err))
+			continue
+		}
+		if scraper == nil {
+			continue
+		}
+		for _, target := range check.Targets {
+			families, err := ScrapeMetrics(ctx, scraper, target)
+			if err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", check.Component.Name, err))
+				continue
+			}
+			GinkgoWriter.Printf("%s: %d metric families from %s\n", check.Component.Name, len(families), target)
+		}
+	}
+	Expect(failed).To(BeEmpty(), "monitoring does not match the config:\n%s", strings.Join(failed, "\n"))
+}
+
+// findMonitors adds the monitors owned by a component CR and their targets to check
+func findMonitors(ctx context.Context, cli client.Client, obj *unstructured.Unstructured, check *MonitoringCheck) (bool, error) {
+	found := false
+	for _, gvk := range []schema.GroupVersionKind{serviceMonitorGVK, podMonitorGVK} {
+		monitors, err := list(ctx, cli, gvk, obj.GetNamespace())
+		if err != nil {
+			if errors.Is(err, ErrNoKindMatch) {
+				continue // Prometheus operator not installed
+			}
+			return false, err
+		}
+		for i := range monitors {
+			if !isOwnedBy(&monitors[i], obj.GetUID()) {
+				continue
+			}
+			found = true
+			check.Monitors = append(check.Monitors, gvk.Kind+" "+monitors[i].GetName())
+			targets, err := monitorTargets(ctx, cli, &monitors[i])
+			if err != nil {
+				return false, err
+			}
+			check.Targets = append(check.Targets, targets...)
+		}
+	}
+	return found, nil
+}
+
+// monitorTargets resolves the Services (ServiceMonitor) or Pods (PodMonitor) selected by a monitor
+// in the namespaces of its namespaceSelector; for a PodMonitor only the first running pod is scraped
+func monitorTargets(ctx context.Context, cli client.Client, monitor *unstructured.Unstructured) ([]MetricsTarget, error) {
+	targetGVK, endpointsField := serviceGVK, "endpoints"
+	if monitor.GetKind() == podMonitorGVK.Kind {
+		targetGVK, endpointsField = podGVK, "podMetricsEndpoints"
+	}
+
+	selector, err := monitorSelector(monitor)
+	if err != nil {
+		return nil, err
+	}
+	var selected []*unstructured.Unstructured
+	for _, namespace := range monitorNamespaces(monitor) {
+		candidates, err := list(ctx, cli, targetGVK, namespace)
+		if err != nil {
+			return nil, err
+		}
+		for i := range candidates {
+			if !selector.Matches(labels.Set(candidates[i].GetLabels())) {
+				continue
+			}
+			if targetGVK == podGVK {
+				if phase, _, _ := unstructured.NestedString(candidates[i].Object, "status", "phase"); phase != "Running" {
+					continue
+				}
+			}
+			selected = append(selected, &candidates[i])
+			if targetGVK == podGVK {
+				break
+			}
+		}
+		if targetGVK == podGVK && len(selected) > 0 {
+			break
+		}
+	}
+
+	endpoints, _, _ := unstructured.NestedSlice(monitor.Object, "spec", endpointsField)
+	var targets []MetricsTarget
+	for _, endpoint := range endpoints {
+		endpointMap, ok := endpoint.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		path, _ := endpointMap["path"].(string)
+		if path == "" {
+			path = "/metrics"
+		}
+		scheme, _ := endpointMap["scheme"].(string)
+		if scheme == "" {
+			scheme = "http"
+		}
+		for _, obj := range selected {
+			port, err := proxyPort(obj, endpointMap)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", monitor.GetKind(), monitor.GetName(), err)
+			}
+			targets = append(targets, MetricsTarget{
+				Kind:      targetGVK.Kind,
+				Namespace: obj.GetNamespace(),
+				Name:      obj.GetName(),
+				Port:      port,
+				Path:      path,
+				Scheme:    scheme,
+			})
+		}
+	}
+	return targets, nil
+}
+
+// monitorSelector returns spec.selector of a monitor with its matchLabels and matchExpressions
+// A selector without any term selects nothing, not every Service or Pod of the namespace
+func monitorSelector(monitor *unstructured.Unstructured) (labels.Selector, error) {
+	labelSelector := &metav1.LabelSelector{}
+	if content, _, _ := unstructured.NestedMap(monitor.Object, "spec", "selector"); content != nil {
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, labelSelector); err != nil {
+			return nil, fmt.Errorf("invalid selector of %s %s: %w", monitor.GetKind(), monitor.GetName(), err)
+		}
+	}
+	if len(labelSelector.MatchLabels) == 0 && len(labelSelector.MatchExpressions) == 0 {
+		return labels.Nothing(), nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector of %s %s: %w", monitor.GetKind(), monitor.GetName(), err)
+	}
+	return selector, nil
+}
+
+// monitorNamespaces returns the namespaces selected by spec.namespaceSelector, "" for all namespaces
+// Without namespaceSelector only the namespace of the monitor is selected
+func monitorNamespaces(monitor *unstructured.Unstructured) []string {
+	if anyNamespace, _, _ := unstructured.NestedBool(monitor.Object, "spec", "namespaceSelector", "any"); anyNamespace {
+		return []string{metav1.NamespaceAll}
+	}
+	if names, _, _ := unstructured.NestedStringSlice(monitor.Object, "spec", "namespaceSelector", "matchNames"); len(names) > 0 {
+		return names
+	}
+	return []string{monitor.GetNamespace()}
+}
+
+// proxyPort resolves the port of a monitor endpoint on a selected Pod or Service to one the API server proxy accepts
+// The pods/proxy subresource needs a number, so a port name is looked up in spec.containers[].ports of the Pod;
+// a ServiceMonitor targetPort is a pod port, it is mapped to the Service port forwarding to it
+func proxyPort(obj *unstructured.Unstructured, endpoint map[string]interface{}) (string, error) {
+	field, port := endpointPort(endpoint)
+	if port == "" {
+		return "", nil
+	}
+	if obj.GetKind() == podGVK.Kind {
+		if _, err := strconv.Atoi(port); err == nil {
+			return port, nil
+		}
+		containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "containers")
+		for _, container := range containers {
+			containerMap, ok := container.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			ports, _, _ := unstructured.NestedSlice(containerMap, "ports")
+			for _, containerPort := range ports {
+				portMap, ok := containerPort.(map[string]interface{})
+				if ok && portMap["name"] == port {
+					return portString(portMap["containerPort"]), nil
+				}
+			}
+		}
+		return "", fmt.Errorf("port %q not found in the containers of Pod %s/%s", port, obj.GetNamespace(), obj.GetName())
+	}
+
+	if field != "targetPort" {
+		return port, nil // a Service port name, accepted by the proxy as is
+	}
+	ports, _, _ := unstructured.NestedSlice(obj.Object, "spec", "ports")
+	for _, servicePort := range ports {
+		portMap, ok := servicePort.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		number := portString(portMap["port"])
+		target := portString(portMap["targetPort"])
+		if target == "" || target == "0" {
+			target = number // targetPort defaults to port
+		}
+		if target == port {
+			return number, nil
+		}
+	}
+	return "", fmt.Errorf("no port of Service %s/%s targets port %s", obj.GetNamespace(), obj.GetName(), port)
+}
+
+// endpointPort returns the field and the port name or number of a monitor endpoint: port, portNumber or targetPort
+func endpointPort(endpoint map[string]interface{}) (string, string) {
+	for _, field := range []string{"port", "portNumber", "targetPort"} {
+		if port := portString(endpoint[field]); port != "" {
+			return field, port
+		}
+	}
+	return "", ""
+}
+
+// portString returns a port name or number of an unstructured object as string, "" if it is not set
+func portString(port interface{}) string {
+	switch port := port.(type) {
+	case string:
+		return port
+	case int64:
+		return strconv.FormatInt(port, 10)
+	case float64:
+		return strconv.FormatFloat(port, 'f', -1, 64)
+	}
+	return ""
+}
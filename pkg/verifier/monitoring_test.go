@@ -0,0 +1,269 @@
+package verifier
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/petrpinkas/config-examples/pkg/apis/rhtas/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const renderedMonitoring = `
+apiVersion: rhtas.redhat.com/v1alpha1
+kind: Securesign
+metadata:
+  name: sample
+spec:
+  ctlog:
+    monitoring:
+      enabled: false
+  fulcio:
+    monitoring:
+      enabled: true
+  rekor:
+    monitoring:
+      enabled: true
+  tuf: {}
+`
+
+const prometheusText = `# HELP fulcio_new_certs Number of issued certificates
+# TYPE fulcio_new_certs counter
+fulcio_new_certs 3
+`
+
+// metricsServer returns a stand-in exposing Prometheus metrics on /metrics
+func metricsServer(body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/metrics" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+}
+
+// standInScraper scrapes every target from server, recording the targets
+func standInScraper(server *httptest.Server, scraped *[]MetricsTarget) MetricsScraper {
+	return ScraperFunc(func(ctx context.Context, target MetricsTarget) ([]byte, error) {
+		*scraped = append(*scraped, target)
+		resp, err := server.Client().Get(server.URL + target.Path)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		return io.ReadAll(resp.Body)
+	})
+}
+
+var _ = Describe("Monitoring", func() {
+	var (
+		rendered v1alpha1.Object
+		objects  []client.Object
+		ref      Ref
+	)
+
+	BeforeEach(func() {
+		rendered = typedFromYAML(renderedMonitoring)
+		ref = Ref{GVK: securesignGVK, Namespace: "ns", Name: "sample"}
+
+		securesign := newSecuresign("ns", "sample")
+		securesign.SetUID("securesign-uid")
+		fulcio := owned(fulcioGVK, "sample", securesign)
+		rekor := owned(rekorGVK, "sample", securesign)
+		ctlog := owned(ctlogGVK, "sample", securesign)
+
+		serviceMonitor := owned(serviceMonitorGVK, "fulcio-metrics", fulcio)
+		setField(serviceMonitor, map[string]interface{}{"app": "fulcio"}, "spec", "selector", "matchLabels")
+		setField(serviceMonitor, []interface{}{map[string]interface{}{"port": "metrics"}}, "spec", "endpoints")
+		service := owned(serviceGVK, "fulcio-server", fulcio)
+		service.SetLabels(map[string]string{"app": "fulcio"})
+
+		podMonitor := owned(podMonitorGVK, "rekor-metrics", rekor)
+		setField(podMonitor, map[string]interface{}{"app": "rekor"}, "spec", "selector", "matchLabels")
+		setField(podMonitor, []interface{}{map[string]interface{}{"port": "2112", "path": "/metrics"}}, "spec", "podMetricsEndpoints")
+		pending := owned(podGVK, "rekor-server-pending", rekor)
+		pending.SetLabels(map[string]string{"app": "rekor"})
+		setField(pending, "Pending", "status", "phase")
+		running := owned(podGVK, "rekor-server-running", rekor)
+		running.SetLabels(map[string]string{"app": "rekor"})
+		setField(running, "Running", "status", "phase")
+
+		objects = []client.Object{securesign, fulcio, rekor, ctlog, serviceMonitor, service, podMonitor, pending, running}
+	})
+
+	It("should read monitoring.enabled from the rendered config", func() {
+		Expect(ExpectedMonitoring(rendered)).To(Equal(map[string]bool{"ctlog": false, "fulcio": true, "rekor": true}))
+		Expect(ExpectedMonitoring(typedFromYAML("apiVersion: rhtas.redhat.com/v1alpha1\nkind: Fulcio\nspec:\n  monitoring:\n    enabled: false\n"))).
+			To(Equal(map[string]bool{"fulcio": false}))
+	})
+
+	It("should match monitors with the config and resolve their targets", func(ctx SpecContext) {
+		cli := fake.NewClientBuilder().WithObjects(objects...).Build()
+		checks, err := CheckMonitoring(ctx, cli, ref, ExpectedMonitoring(rendered))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checks).To(HaveLen(3))
+		for _, check := range checks {
+			Expect(check.Err).NotTo(HaveOccurred(), check.Component.Name)
+		}
+
+		Expect(checks[0].Component).To(Equal(Fulcio))
+		Expect(checks[0].Monitors).To(ConsistOf("ServiceMonitor fulcio-metrics"))
+		Expect(checks[0].Targets).To(ConsistOf(MetricsTarget{Kind: "Service", Namespace: "ns", Name: "fulcio-server", Port: "metrics", Path: "/metrics", Scheme: "http"}))
+		Expect(checks[1].Component).To(Equal(Rekor))
+		Expect(checks[1].Targets).To(ConsistOf(MetricsTarget{Kind: "Pod", Namespace: "ns", Name: "rekor-server-running", Port: "2112", Path: "/metrics", Scheme: "http"}))
+		Expect(checks[2].Component).To(Equal(CTlog))
+		Expect(checks[2].Monitors).To(BeEmpty())
+	})
+
+	It("should honor label expressions, the namespace selector and numeric ports", func(ctx SpecContext) {
+		monitor := fromYAML(`
+apiVersion: monitoring.coreos.com/v1
+kind: ServiceMonitor
+metadata:
+  name: tsa-metrics
+  namespace: ns
+spec:
+  selector:
+    matchExpressions:
+      - {key: app, operator: In, values: [tsa]}
+  namespaceSelector:
+    matchNames: [other]
+  endpoints:
+    - targetPort: 2112
+`)
+		tsa := fromYAML("apiVersion: v1\nkind: Service\nmetadata:\n  name: tsa-server\n  namespace: other\n  labels:\n    app: tsa\n" +
+			"spec:\n  ports:\n    - {name: http, port: 80, targetPort: 3000}\n    - {name: metrics, port: 8080, targetPort: 2112}\n")
+		local := fromYAML("apiVersion: v1\nkind: Service\nmetadata:\n  name: tsa-local\n  namespace: ns\n  labels:\n    app: tsa\n" +
+			"spec:\n  ports:\n    - {name: metrics, port: 2112}\n")
+		unlabeled := fromYAML("apiVersion: v1\nkind: Service\nmetadata:\n  name: other-server\n  namespace: other\n")
+		cli := fake.NewClientBuilder().WithObjects(tsa, local, unlabeled).Build()
+
+		targets, err := monitorTargets(ctx, cli, monitor)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(targets).To(ConsistOf(MetricsTarget{Kind: "Service", Namespace: "other", Name: "tsa-server", Port: "8080", Path: "/metrics", Scheme: "http"}))
+
+		unstructured.RemoveNestedField(monitor.Object, "spec", "selector")
+		targets, err = monitorTargets(ctx, cli, monitor)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(targets).To(BeEmpty())
+
+		setField(monitor, true, "spec", "namespaceSelector", "any")
+		setField(monitor, map[string]interface{}{"app": "tsa"}, "spec", "selector", "matchLabels")
+		targets, err = monitorTargets(ctx, cli, monitor)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(targets).To(HaveLen(2))
+	})
+
+	It("should resolve named pod ports and service target ports to ports the proxy accepts", func(ctx SpecContext) {
+		monitor := fromYAML(`
+apiVersion: monitoring.coreos.com/v1
+kind: PodMonitor
+metadata:
+  name: rekor-metrics
+  namespace: ns
+spec:
+  selector:
+    matchLabels: {app: rekor}
+  podMetricsEndpoints:
+    - port: metrics
+`)
+		pod := fromYAML(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: rekor-server
+  namespace: ns
+  labels: {app: rekor}
+spec:
+  containers:
+    - name: redis
+      ports: [{name: redis, containerPort: 6379}]
+    - name: rekor
+      ports: [{name: http, containerPort: 3000}, {name: metrics, containerPort: 2112}]
+status:
+  phase: Running
+`)
+		cli := fake.NewClientBuilder().WithObjects(pod).Build()
+		targets, err := monitorTargets(ctx, cli, monitor)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(targets).To(ConsistOf(MetricsTarget{Kind: "Pod", Namespace: "ns", Name: "rekor-server", Port: "2112", Path: "/metrics", Scheme: "http"}))
+
+		setField(monitor, []interface{}{map[string]interface{}{"port": "missing"}}, "spec", "podMetricsEndpoints")
+		_, err = monitorTargets(ctx, cli, monitor)
+		Expect(err).To(MatchError(`PodMonitor rekor-metrics: port "missing" not found in the containers of Pod ns/rekor-server`))
+
+		serviceMonitor := fromYAML(`
+apiVersion: monitoring.coreos.com/v1
+kind: ServiceMonitor
+metadata:
+  name: rekor-metrics
+  namespace: ns
+spec:
+  selector:
+    matchLabels: {app: rekor}
+  endpoints:
+    - targetPort: metrics
+`)
+		service := fromYAML(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: rekor-server
+  namespace: ns
+  labels: {app: rekor}
+spec:
+  ports:
+    - {name: http, port: 80, targetPort: http}
+    - {name: monitoring, port: 8080, targetPort: metrics}
+`)
+		cli = fake.NewClientBuilder().WithObjects(service).Build()
+		targets, err = monitorTargets(ctx, cli, serviceMonitor)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(targets).To(ConsistOf(MetricsTarget{Kind: "Service", Namespace: "ns", Name: "rekor-server", Port: "8080", Path: "/metrics", Scheme: "http"}))
+
+		setField(serviceMonitor, []interface{}{map[string]interface{}{"targetPort": int64(9090)}}, "spec", "endpoints")
+		_, err = monitorTargets(ctx, cli, serviceMonitor)
+		Expect(err).To(MatchError("ServiceMonitor rekor-metrics: no port of Service ns/rekor-server targets port 9090"))
+	})
+
+	It("should report monitors that exist although monitoring is disabled, and missing ones", func(ctx SpecContext) {
+		cli := fake.NewClientBuilder().WithObjects(objects...).Build()
+		checks, err := CheckMonitoring(ctx, cli, ref, map[string]bool{"fulcio": false, "ctlog": true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checks).To(HaveLen(2))
+		Expect(checks[0].Err).To(MatchError("monitoring is disabled but ServiceMonitor fulcio-metrics exist(s)"))
+		Expect(checks[1].Err).To(MatchError("monitoring is enabled but no ServiceMonitor or PodMonitor exists"))
+	})
+
+	It("should scrape the targets and validate the Prometheus text format", func(ctx SpecContext) {
+		server := metricsServer(prometheusText)
+		DeferCleanup(server.Close)
+		cli := fake.NewClientBuilder().WithObjects(objects...).Build()
+
+		var scraped []MetricsTarget
+		VerifyMonitoring(ctx, cli, "ns", "sample", securesignGVK, rendered, standInScraper(server, &scraped))
+		Expect(scraped).To(HaveLen(2))
+
+		families, err := ScrapeMetrics(ctx, standInScraper(server, &scraped), scraped[0])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(families).To(Equal([]string{"fulcio_new_certs"}))
+	})
+
+	It("should reject responses that are not Prometheus text format", func(ctx SpecContext) {
+		server := metricsServer("fulcio_new_certs three\n")
+		DeferCleanup(server.Close)
+
+		var scraped []MetricsTarget
+		_, err := ScrapeMetrics(ctx, standInScraper(server, &scraped), MetricsTarget{Kind: "Service", Namespace: "ns", Name: "fulcio-server", Port: "metrics", Path: "/metrics"})
+		Expect(err).To(MatchError(ContainSubstring("invalid Prometheus text format from Service ns/fulcio-server:metrics/metrics")))
+
+		_, err = ScrapeMetrics(ctx, standInScraper(server, &scraped), MetricsTarget{Path: "/missing"})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,24 @@
+package verifier
+
+import (
+	"context"
+
+	"github.com/petrpinkas/config-examples/pkg/apis/rhtas/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var rekorGVK = v1alpha1.RekorGVK
+
+// Rekor is the transparency log
+var Rekor = Component{Name: "rekor", GVK: rekorGVK, SpecField: "rekor", HealthPath: "/api/v1/log"}
+
+// VerifyRekor waits for the Rekor CR and the workloads it owns to be ready
+func VerifyRekor(ctx context.Context, cli client.Client, namespace, name string) {
+	VerifyComponents(ctx, cli, namespace, name, rekorGVK)
+}
+
+// rekorSecrets returns the Rekor signer key
+func rekorSecrets(obj *unstructured.Unstructured) []SecretCheck {
+	return []SecretCheck{lookupSecretRef(obj, "Rekor signer key", "signer", "keyRef")}
+}
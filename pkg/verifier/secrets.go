@@ -0,0 +1,167 @@
+package verifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	// ErrRefNotReported means the component reports no reference to the secret, neither in status nor in spec
+	ErrRefNotReported = errors.New("secret reference not reported")
+	// ErrSecretNotFound means the referenced Secret does not exist
+	ErrSecretNotFound = errors.New("secret not found")
+	// ErrKeyMissing means the referenced Secret does not contain the key or the value is empty
+	ErrKeyMissing = errors.New("key missing or empty")
+)
+
+// SecretRef references a key of a Secret, Key is empty when only the Secret has to exist
+type SecretRef struct {
+	Name string
+	Key  string
+}
+
+// String returns "name/key" or "name"
+func (r SecretRef) String() string {
+	if r.Key == "" {
+		return r.Name
+	}
+	return r.Name + "/" + r.Key
+}
+
+// SecretCheck is the result of checking a secret a component depends on
+type SecretCheck struct {
+	Component string
+	// Description names the secret, e.g. "Fulcio CA certificate"
+	Description string
+	Ref         SecretRef
+	// Err is nil if the Secret exists and contains a non-empty value for Ref.Key
+	Err error
+}
+
+// String returns a one line summary such as "fulcio: Fulcio CA certificate (fulcio-cert/cert): ok"
+func (c SecretCheck) String() string {
+	result := "ok"
+	if c.Err != nil {
+		result = c.Err.Error()
+	}
+	if c.Ref.Name == "" {
+		return fmt.Sprintf("%s: %s: %s", c.Component, c.Description, result)
+	}
+	return fmt.Sprintf("%s: %s (%s): %s", c.Component, c.Description, c.Ref, result)
+}
+
+// CheckSecrets checks the secrets of the components of a Securesign, or of a single component CR:
+// Fulcio CA and key, CTlog keys and root certificates, Rekor signer key, TSA certificate chain and TUF keys
+// References are read from the component status (filled in by the operator) and fall back to the spec
+// Components that were not created yet are skipped, use CheckComponents to verify them
+func CheckSecrets(ctx context.Context, cli client.Client, ref Ref) ([]SecretCheck, error) {
+	components, err := discoverComponents(ctx, cli, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	var checks []SecretCheck
+	for _, d := range components {
+		if d.obj == nil {
+			continue
+		}
+		for _, check := range secretsFor(d.component, d.obj) {
+			if check.Err == nil {
+				_, check.Err = getSecretValue(ctx, cli, d.obj.GetNamespace(), check.Ref)
+				if check.Err != nil && !isSecretCheckError(check.Err) {
+					return nil, check.Err
+				}
+			}
+			checks = append(checks, check)
+		}
+	}
+	return checks, nil
+}
+
+// VerifySecrets checks the component secrets and fails the current spec listing every missing secret or key
+func VerifySecrets(ctx context.Context, cli client.Client, namespace, name string, gvk schema.GroupVersionKind) {
+	checks, err := CheckSecrets(ctx, cli, Ref{GVK: gvk, Namespace: namespace, Name: name})
+	Expect(err).NotTo(HaveOccurred())
+
+	var failed []string
+	for _, check := range checks {
+		if check.Err != nil {
+			failed = append(failed, check.String())
+		}
+	}
+	Expect(failed).To(BeEmpty(), "component secrets are incomplete:\n%s", strings.Join(failed, "\n"))
+}
+
+// secretsFor returns the secrets a component depends on, with Err set when the reference is not reported
+func secretsFor(component Component, obj *unstructured.Unstructured) []SecretCheck {
+	var checks []SecretCheck
+	switch component.Name {
+	case Fulcio.Name:
+		checks = fulcioSecrets(obj)
+	case CTlog.Name:
+		checks = ctlogSecrets(obj)
+	case Rekor.Name:
+		checks = rekorSecrets(obj)
+	case TSA.Name:
+		checks = tsaSecrets(obj)
+	case TUF.Name:
+		checks = tufSecrets(obj)
+	}
+	for i := range checks {
+		checks[i].Component = component.Name
+	}
+	return checks
+}
+
+// lookupSecretRef reads a {name, key} reference at path below status, falling back to spec
+func lookupSecretRef(obj *unstructured.Unstructured, description string, path ...string) SecretCheck {
+	for _, root := range []string{"status", "spec"} {
+		if ref, ok := secretRefAt(obj.Object, append([]string{root}, path...)...); ok {
+			return SecretCheck{Description: description, Ref: ref}
+		}
+	}
+	return SecretCheck{Description: description, Err: fmt.Errorf("%w at %s", ErrRefNotReported, strings.Join(path, "."))}
+}
+
+// secretRefAt reads a {name, key} reference from a nested map
+func secretRefAt(obj map[string]interface{}, path ...string) (SecretRef, bool) {
+	name, _, _ := unstructured.NestedString(obj, append(path, "name")...)
+	if name == "" {
+		return SecretRef{}, false
+	}
+	key, _, _ := unstructured.NestedString(obj, append(path, "key")...)
+	return SecretRef{Name: name, Key: key}, true
+}
+
+// getSecretValue returns the value of ref.Key, or nil if ref.Key is empty and the Secret exists
+func getSecretValue(ctx context.Context, cli client.Client, namespace string, ref SecretRef) ([]byte, error) {
+	secret := &corev1.Secret{}
+	if err := cli.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, ErrSecretNotFound
+		}
+		return nil, fmt.Errorf("failed to get secret %s/%s: %w", namespace, ref.Name, err)
+	}
+	if ref.Key == "" {
+		return nil, nil
+	}
+	value := secret.Data[ref.Key]
+	if len(value) == 0 {
+		return nil, ErrKeyMissing
+	}
+	return value, nil
+}
+
+// isSecretCheckError returns true for errors reported per check instead of aborting the check
+func isSecretCheckError(err error) bool {
+	return errors.Is(err, ErrSecretNotFound) || errors.Is(err, ErrKeyMissing)
+}
@@ -0,0 +1,110 @@
+package verifier
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// secret returns a Secret in namespace ns with the given data
+func secret(name string, data map[string]string) *corev1.Secret {
+	s := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name}, Data: map[string][]byte{}}
+	for k, v := range data {
+		s.Data[k] = []byte(v)
+	}
+	return s
+}
+
+// setField sets a nested field of obj
+func setField(obj *unstructured.Unstructured, value interface{}, path ...string) {
+	Expect(unstructured.SetNestedField(obj.Object, value, path...)).To(Succeed())
+}
+
+// ref returns a {name, key} reference as used by the operator
+func ref(name, key string) map[string]interface{} {
+	return map[string]interface{}{"name": name, "key": key}
+}
+
+var _ = Describe("CheckSecrets", func() {
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("should check the secrets reported by each component", func() {
+		securesign := newSecuresign("ns", "sample")
+		securesign.SetUID("securesign-uid")
+
+		fulcio := owned(fulcioGVK, "sample", securesign)
+		setField(fulcio, ref("fulcio-cert", "cert"), "status", "certificate", "caRef")
+		setField(fulcio, ref("fulcio-cert", "private"), "status", "certificate", "privateKeyRef")
+
+		rekor := owned(rekorGVK, "sample", securesign)
+		setField(rekor, ref("rekor-signer", "private"), "status", "signer", "keyRef")
+
+		ctlog := owned(ctlogGVK, "sample", securesign)
+		setField(ctlog, ref("ctlog-keys", "private"), "status", "privateKeyRef")
+		// public key reference only provided in spec
+		setField(ctlog, ref("ctlog-keys", "public"), "spec", "publicKeyRef")
+		setField(ctlog, []interface{}{ref("fulcio-cert", "cert")}, "status", "rootCertificates")
+
+		tsa := owned(tsaGVK, "sample", securesign)
+
+		tuf := owned(tufGVK, "sample", securesign)
+		setField(tuf, "tuf-root-keys", "spec", "rootKeySecretRef", "name")
+		setField(tuf, []interface{}{
+			map[string]interface{}{"name": "rekor.pub"},
+			map[string]interface{}{"name": "ctfe.pub"},
+		}, "spec", "keys")
+		setField(tuf, []interface{}{
+			map[string]interface{}{"name": "rekor.pub", "secretRef": ref("rekor-pub", "public")},
+		}, "status", "keys")
+
+		cli := fake.NewClientBuilder().WithObjects(securesign, fulcio, rekor, ctlog, tsa, tuf,
+			secret("fulcio-cert", map[string]string{"cert": "CERT", "private": "KEY"}),
+			secret("rekor-signer", map[string]string{"private": ""}),
+			secret("ctlog-keys", map[string]string{"private": "KEY", "public": "PUB"}),
+			secret("tuf-root-keys", nil),
+		).Build()
+
+		checks, err := CheckSecrets(ctx, cli, Ref{GVK: securesignGVK, Namespace: "ns", Name: "sample"})
+		Expect(err).NotTo(HaveOccurred())
+
+		results := map[string]error{}
+		for _, check := range checks {
+			results[check.Component+": "+check.Description] = check.Err
+		}
+		Expect(results).To(HaveLen(10))
+		Expect(results["fulcio: Fulcio CA certificate"]).To(Succeed())
+		Expect(results["fulcio: Fulcio private key"]).To(Succeed())
+		Expect(results["ctlog: CTlog private key"]).To(Succeed())
+		Expect(results["ctlog: CTlog public key"]).To(Succeed())
+		Expect(results["ctlog: CTlog root certificate"]).To(Succeed())
+		Expect(results["tuf: TUF root keys"]).To(Succeed())
+		Expect(errors.Is(results["rekor: Rekor signer key"], ErrKeyMissing)).To(BeTrue())
+		Expect(errors.Is(results["tsa: TSA certificate chain"], ErrRefNotReported)).To(BeTrue())
+		Expect(errors.Is(results["tuf: TUF key rekor.pub"], ErrSecretNotFound)).To(BeTrue())
+		Expect(errors.Is(results["tuf: TUF key ctfe.pub"], ErrRefNotReported)).To(BeTrue())
+	})
+
+	It("should fail the spec listing incomplete secrets", func(ctx SpecContext) {
+		fulcio := owned(fulcioGVK, "fulcio-sample", newSecuresign("ns", "sample"))
+		setField(fulcio, ref("fulcio-cert", "cert"), "status", "certificate", "caRef")
+		setField(fulcio, ref("fulcio-cert", "private"), "status", "certificate", "privateKeyRef")
+		cli := fake.NewClientBuilder().WithObjects(fulcio, secret("fulcio-cert", map[string]string{"cert": "CERT"})).Build()
+
+		failures := InterceptGomegaFailures(func() {
+			VerifySecrets(ctx, cli, "ns", "fulcio-sample", fulcioGVK)
+		})
+		Expect(failures).To(HaveLen(1))
+		Expect(failures[0]).To(ContainSubstring("fulcio: Fulcio private key (fulcio-cert/private): key missing or empty"))
+		Expect(failures[0]).NotTo(ContainSubstring("Fulcio CA certificate"))
+	})
+})
@@ -2,25 +2,25 @@ package verifier
 
 import (
 	"context"
+	"time"
 
+	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/types"
-	"k8s.io/apimachinery/pkg/api/errors"
+	"github.com/petrpinkas/config-examples/pkg/apis/rhtas/v1alpha1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var (
-	securesignGVK = schema.GroupVersionKind{
-		Group:   "rhtas.redhat.com",
-		Version: "v1alpha1",
-		Kind:    "Securesign",
-	}
+	securesignGVK = v1alpha1.SecuresignGVK
 )
 
 // Get retrieves a resource instance by GroupVersionKind
-func Get(ctx context.Context, cli client.Client, namespace, name string, gvk schema.GroupVersionKind) *unstructured.Unstructured {
+// On failure it returns a *GetError; use errors.Is(err, ErrNotFound) to check if the resource does not exist
+// and IsRetryable(err) to check if waiting may help (e.g., not for Forbidden or a missing CRD)
+func Get(ctx context.Context, cli client.Client, namespace, name string, gvk schema.GroupVersionKind) (*unstructured.Unstructured, error) {
 	obj := &unstructured.Unstructured{}
 	obj.SetGroupVersionKind(gvk)
 
@@ -29,17 +29,14 @@ func Get(ctx context.Context, cli client.Client, namespace, name string, gvk sch
 		Name:      name,
 	}, obj)
 
-	if errors.IsNotFound(err) {
-		return nil
-	}
 	if err != nil {
-		return nil
+		return nil, newGetError(gvk, namespace, name, err)
 	}
-	return obj
+	return obj, nil
 }
 
 // GetSecuresign retrieves a Securesign CR instance (backward compatibility)
-func GetSecuresign(ctx context.Context, cli client.Client, namespace, name string) *unstructured.Unstructured {
+func GetSecuresign(ctx context.Context, cli client.Client, namespace, name string) (*unstructured.Unstructured, error) {
 	return Get(ctx, cli, namespace, name, securesignGVK)
 }
 
@@ -72,19 +69,49 @@ func IsReady(obj *unstructured.Unstructured) bool {
 	return false
 }
 
-// Verify waits for a resource to be ready
+// Verify waits for a resource to be ready using DefaultWaitOptions()
+// It fails fast on errors that waiting cannot fix, such as Forbidden or a missing CRD
 func Verify(ctx context.Context, cli client.Client, namespace, name string, gvk schema.GroupVersionKind) {
-	Eventually(func(g Gomega) *unstructured.Unstructured {
-		obj := Get(ctx, cli, namespace, name, gvk)
-		g.Expect(obj).NotTo(BeNil())
-		return obj
-	}).WithContext(ctx).Should(Not(BeNil()))
-
-	Eventually(func(g Gomega) bool {
-		obj := Get(ctx, cli, namespace, name, gvk)
-		g.Expect(obj).NotTo(BeNil())
-		return IsReady(obj)
-	}).WithContext(ctx).Should(BeTrue())
+	VerifyWithOptions(ctx, cli, namespace, name, gvk, DefaultWaitOptions())
+}
+
+// VerifyWithOptions waits for a resource to be ready and fails the current spec otherwise
+// It is a thin Gomega wrapper over WaitFor; state changes are logged to GinkgoWriter unless opts.OnProgress is set
+// A diagnostics bundle is collected before failing if enabled with SetDiagnostics
+func VerifyWithOptions(ctx context.Context, cli client.Client, namespace, name string, gvk schema.GroupVersionKind, opts WaitOptions) {
+	if opts.OnProgress == nil {
+		opts.OnProgress = logStateChanges()
+	}
+	ref := Ref{GVK: gvk, Namespace: namespace, Name: name}
+	err := WaitFor(ctx, cli, ref, Ready, opts)
+	if err != nil {
+		collectOnFailure(ctx, cli, ref)
+	}
+	Expect(err).To(Succeed())
+}
+
+// VerifyExpectations waits until a resource meets all expectations, e.g. FulcioAvailable=True
+// or a negative expectation such as Ready=False with reason Failure
+func VerifyExpectations(ctx context.Context, cli client.Client, namespace, name string, gvk schema.GroupVersionKind, expectations Expectations) {
+	opts := DefaultWaitOptions()
+	opts.OnProgress = logStateChanges()
+	ref := Ref{GVK: gvk, Namespace: namespace, Name: name}
+	err := WaitFor(ctx, cli, ref, expectations.Condition(), opts)
+	if err != nil {
+		collectOnFailure(ctx, cli, ref)
+	}
+	Expect(err).To(Succeed(), "expected %s", expectations)
+}
+
+// logStateChanges returns a progress callback printing to GinkgoWriter whenever the observed state changes
+func logStateChanges() func(Progress) {
+	var last string
+	return func(p Progress) {
+		if state := p.String(); state != last {
+			GinkgoWriter.Printf("[%s] %s\n", p.Elapsed.Round(time.Second), state)
+			last = state
+		}
+	}
 }
 
 // VerifySecuresign waits for the Securesign CR to be ready (backward compatibility)
@@ -0,0 +1,101 @@
+package verifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestVerifier(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Verifier Package Suite")
+}
+
+// newSecuresign returns a Securesign with the given status conditions
+func newSecuresign(namespace, name string, conditions ...map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(securesignGVK)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	if len(conditions) > 0 {
+		items := make([]interface{}, len(conditions))
+		for i, c := range conditions {
+			items[i] = c
+		}
+		Expect(unstructured.SetNestedSlice(obj.Object, items, "status", "conditions")).To(Succeed())
+	}
+	return obj
+}
+
+// failingGetClient returns a fake client whose Get always fails with err
+func failingGetClient(err error) client.Client {
+	return fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			return err
+		},
+	}).Build()
+}
+
+var _ = Describe("Get", func() {
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("should return the resource", func() {
+		cli := fake.NewClientBuilder().WithObjects(newSecuresign("ns", "sample")).Build()
+		obj, err := GetSecuresign(ctx, cli, "ns", "sample")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(obj.GetName()).To(Equal("sample"))
+	})
+
+	DescribeTable("should classify errors",
+		func(clientErr error, reason error, retryable bool) {
+			obj, err := Get(ctx, failingGetClient(clientErr), "ns", "sample", securesignGVK)
+			Expect(obj).To(BeNil())
+			Expect(errors.Is(err, reason)).To(BeTrue())
+			Expect(errors.Is(err, clientErr)).To(BeTrue())
+			Expect(IsRetryable(err)).To(Equal(retryable))
+			Expect(err.Error()).To(ContainSubstring("Securesign ns/sample"))
+			Expect(err.Error()).To(ContainSubstring(clientErr.Error()))
+		},
+		Entry("not found", apierrors.NewNotFound(schema.GroupResource{Resource: "securesigns"}, "sample"), ErrNotFound, true),
+		Entry("forbidden", apierrors.NewForbidden(schema.GroupResource{Resource: "securesigns"}, "sample", errors.New("RBAC")), ErrForbidden, false),
+		Entry("unauthorized", apierrors.NewUnauthorized("bad token"), ErrUnauthorized, false),
+		Entry("no kind match", &meta.NoKindMatchError{GroupKind: securesignGVK.GroupKind(), SearchedVersions: []string{"v1alpha1"}}, ErrNoKindMatch, false),
+		Entry("timeout", apierrors.NewTimeoutError("slow", 1), ErrTimeout, true),
+		Entry("other", errors.New("connection reset"), ErrUnknown, true),
+	)
+})
+
+var _ = Describe("Verify", func() {
+	It("should succeed once the resource is ready", func(ctx SpecContext) {
+		ready := map[string]interface{}{"type": "Ready", "status": "True"}
+		cli := fake.NewClientBuilder().WithObjects(newSecuresign("ns", "sample", ready)).Build()
+		VerifySecuresign(ctx, cli, "ns", "sample")
+	})
+
+	It("should fail fast with the underlying message on non-retryable errors", func(ctx SpecContext) {
+		cli := failingGetClient(&meta.NoKindMatchError{GroupKind: securesignGVK.GroupKind(), SearchedVersions: []string{"v1alpha1"}})
+
+		start := time.Now()
+		failures := InterceptGomegaFailures(func() {
+			VerifySecuresign(ctx, cli, "ns", "sample")
+		})
+		Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+		Expect(failures).NotTo(BeEmpty())
+		Expect(failures[0]).To(ContainSubstring("no matches for kind"))
+	})
+})
@@ -0,0 +1,18 @@
+package verifier
+
+import (
+	"context"
+
+	"github.com/petrpinkas/config-examples/pkg/apis/rhtas/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var trillianGVK = v1alpha1.TrillianGVK
+
+// Trillian is the Merkle tree storage backing Rekor and CTlog
+var Trillian = Component{Name: "trillian", GVK: trillianGVK, SpecField: "trillian"}
+
+// VerifyTrillian waits for the Trillian CR and the workloads it owns to be ready
+func VerifyTrillian(ctx context.Context, cli client.Client, namespace, name string) {
+	VerifyComponents(ctx, cli, namespace, name, trillianGVK)
+}
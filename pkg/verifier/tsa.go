@@ -0,0 +1,24 @@
+package verifier
+
+import (
+	"context"
+
+	"github.com/petrpinkas/config-examples/pkg/apis/rhtas/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var tsaGVK = v1alpha1.TimestampAuthorityGVK
+
+// TSA is the RFC 3161 timestamp authority
+var TSA = Component{Name: "tsa", GVK: tsaGVK, SpecField: "tsa", HealthPath: "/api/v1/timestamp/certchain"}
+
+// VerifyTSA waits for the TimestampAuthority CR and the workloads it owns to be ready
+func VerifyTSA(ctx context.Context, cli client.Client, namespace, name string) {
+	VerifyComponents(ctx, cli, namespace, name, tsaGVK)
+}
+
+// tsaSecrets returns the TSA certificate chain
+func tsaSecrets(obj *unstructured.Unstructured) []SecretCheck {
+	return []SecretCheck{lookupSecretRef(obj, "TSA certificate chain", "signer", "certificateChain", "certificateChainRef")}
+}
@@ -0,0 +1,62 @@
+package verifier
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/petrpinkas/config-examples/pkg/apis/rhtas/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var tufGVK = v1alpha1.TufGVK
+
+// TUF serves the trust root used by clients
+var TUF = Component{Name: "tuf", GVK: tufGVK, SpecField: "tuf", HealthPath: "/root.json"}
+
+// VerifyTUF waits for the Tuf CR and the workloads it owns to be ready
+func VerifyTUF(ctx context.Context, cli client.Client, namespace, name string) {
+	VerifyComponents(ctx, cli, namespace, name, tufGVK)
+}
+
+// tufSecrets returns the root keys secret and a secret for every key listed in spec.keys
+// The operator fills in status.keys with the secret holding each key
+func tufSecrets(obj *unstructured.Unstructured) []SecretCheck {
+	var checks []SecretCheck
+	if name, _, _ := unstructured.NestedString(obj.Object, "spec", "rootKeySecretRef", "name"); name != "" {
+		checks = append(checks, SecretCheck{Description: "TUF root keys", Ref: SecretRef{Name: name}})
+	}
+
+	// Secret references by key name, spec first so that status wins
+	refs := map[string]SecretRef{}
+	for _, path := range [][]string{{"spec", "keys"}, {"status", "keys"}} {
+		keys, _, _ := unstructured.NestedSlice(obj.Object, path...)
+		for _, key := range keys {
+			keyMap, ok := key.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := keyMap["name"].(string)
+			if ref, ok := secretRefAt(keyMap, "secretRef"); ok && name != "" {
+				refs[name] = ref
+			}
+		}
+	}
+
+	keys, _, _ := unstructured.NestedSlice(obj.Object, "spec", "keys")
+	for _, key := range keys {
+		keyMap, ok := key.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := keyMap["name"].(string)
+		check := SecretCheck{Description: "TUF key " + name}
+		if ref, ok := refs[name]; ok {
+			check.Ref = ref
+		} else {
+			check.Err = fmt.Errorf("%w for key %s", ErrRefNotReported, name)
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
@@ -0,0 +1,178 @@
+package verifier
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/petrpinkas/config-examples/pkg/apis/rhtas/v1alpha1"
+	"github.com/petrpinkas/config-examples/pkg/clients"
+	"github.com/theupdateframework/go-tuf/v2/metadata"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TUFRepository describes a published TUF repository that passed the client workflow:
+// root, timestamp, snapshot and targets metadata were fetched and their signatures and expiry validated
+type TUFRepository struct {
+	URL string
+	// Versions and Expires are indexed by role name, e.g. "root" or "timestamp"
+	Versions map[string]int64
+	Expires  map[string]time.Time
+	// Targets lists the target names of the top-level targets role
+	Targets []string
+	// Missing lists the configured keys that are not published as target
+	Missing []string
+	// RootPinned is false if root.json was trusted on first use, true if it was checked against pinned root keys
+	// or replaced by a trusted root
+	RootPinned bool
+}
+
+// String returns a one line summary such as "https://tuf.example.com: root v1 (pinned), targets v1 expiring ..., 4 target(s)"
+func (r *TUFRepository) String() string {
+	trust := "pinned"
+	if !r.RootPinned {
+		trust = "trusted on first use"
+	}
+	return fmt.Sprintf("%s: root v%d (%s), targets v%d expiring %s, %d target(s)",
+		r.URL, r.Versions[metadata.ROOT], trust, r.Versions[metadata.TARGETS],
+		r.Expires[metadata.TARGETS].Format(time.RFC3339), len(r.Targets))
+}
+
+// ExpectedTUFTargets returns the key names configured in spec.tuf.keys of a Securesign
+// or spec.keys of a Tuf, e.g. "rekor.pub" or "fulcio_v1.crt.pem"
+func ExpectedTUFTargets(rendered v1alpha1.Object) []string {
+	var keys []v1alpha1.TufKey
+	switch obj := rendered.(type) {
+	case *v1alpha1.Securesign:
+		if obj.Spec.Tuf != nil {
+			keys = obj.Spec.Tuf.Keys
+		}
+	case *v1alpha1.Tuf:
+		keys = obj.Spec.Keys
+	}
+
+	var names []string
+	for _, key := range keys {
+		if key.Name != "" {
+			names = append(names, key.Name)
+		}
+	}
+	return names
+}
+
+// CheckTUFRepository runs the TUF client workflow against the repository at baseURL: newer root versions,
+// timestamp.json, snapshot.json and targets.json are downloaded and their signatures, versions and expiry validated
+// The initial root.json is checked against the root keys or replaced by the trusted root given with pins, see
+// clients.NewTUFUpdater; without pins it is trusted on first use and RootPinned is false
+// Every name of expected that is not a target of the repository is reported in Missing
+func CheckTUFRepository(ctx context.Context, baseURL string, expected []string, opts ProbeOptions, pins ...clients.TUFOption) (*TUFRepository, error) {
+	up, err := clients.NewTUFUpdater(ctx, probeClient(opts), baseURL, pins...)
+	if err != nil {
+		return nil, err
+	}
+	pinned := &clients.TUFOptions{}
+	for _, pin := range pins {
+		pin(pinned)
+	}
+
+	trusted := up.GetTrustedMetadataSet()
+	repo := &TUFRepository{
+		URL:        strings.TrimSuffix(baseURL, "/"),
+		RootPinned: pinned.Root != nil || len(pinned.RootKeys) > 0,
+		Versions: map[string]int64{
+			metadata.ROOT:      trusted.Root.Signed.Version,
+			metadata.TIMESTAMP: trusted.Timestamp.Signed.Version,
+			metadata.SNAPSHOT:  trusted.Snapshot.Signed.Version,
+			metadata.TARGETS:   trusted.Targets[metadata.TARGETS].Signed.Version,
+		},
+		Expires: map[string]time.Time{
+			metadata.ROOT:      trusted.Root.Signed.Expires,
+			metadata.TIMESTAMP: trusted.Timestamp.Signed.Expires,
+			metadata.SNAPSHOT:  trusted.Snapshot.Signed.Expires,
+			metadata.TARGETS:   trusted.Targets[metadata.TARGETS].Signed.Expires,
+		},
+	}
+	targets := up.GetTopLevelTargets()
+	for name := range targets {
+		repo.Targets = append(repo.Targets, name)
+	}
+	sort.Strings(repo.Targets)
+	for _, name := range expected {
+		if _, ok := targets[name]; !ok {
+			repo.Missing = append(repo.Missing, name)
+		}
+	}
+	return repo, nil
+}
+
+// VerifyTUFRepository validates the TUF repository published by a Securesign (or a Tuf CR)
+// and fails the current spec if the metadata is invalid or a key configured in the rendered config is not a target
+// root.json has to be signed with the root keys of the Secret in rootKeySecretRef of the rendered config;
+// only without rootKeySecretRef it is trusted on first use
+func VerifyTUFRepository(ctx context.Context, cli client.Client, namespace, name string, gvk schema.GroupVersionKind, rendered v1alpha1.Object, probeOpts ProbeOptions) {
+	endpoints, err := ResolveEndpoints(ctx, cli, Ref{GVK: gvk, Namespace: namespace, Name: name})
+	Expect(err).NotTo(HaveOccurred())
+
+	var url string
+	for _, endpoint := range endpoints {
+		if endpoint.Component.Name == TUF.Name {
+			url = endpoint.URL
+		}
+	}
+	Expect(url).NotTo(BeEmpty(), "no external URL for the TUF repository of %s/%s", namespace, name)
+
+	var pins []clients.TUFOption
+	if secretName := rootKeySecretName(rendered); secretName != "" {
+		keys, err := tufRootKeys(ctx, cli, namespace, secretName)
+		Expect(err).NotTo(HaveOccurred())
+		pins = append(pins, clients.WithRootKeys(keys...))
+	}
+	repo, err := CheckTUFRepository(ctx, url, ExpectedTUFTargets(rendered), probeOpts, pins...)
+	Expect(err).NotTo(HaveOccurred())
+	GinkgoWriter.Printf("%s\n", repo)
+	Expect(repo.Missing).To(BeEmpty(), "configured keys are not published by %s, targets: %v", repo.URL, repo.Targets)
+}
+
+// rootKeySecretName returns spec.tuf.rootKeySecretRef of a Securesign or spec.rootKeySecretRef of a Tuf
+func rootKeySecretName(rendered v1alpha1.Object) string {
+	var spec *v1alpha1.TufSpec
+	switch obj := rendered.(type) {
+	case *v1alpha1.Securesign:
+		spec = obj.Spec.Tuf
+	case *v1alpha1.Tuf:
+		spec = &obj.Spec
+	}
+	if spec == nil || spec.RootKeySecretRef == nil {
+		return ""
+	}
+	return spec.RootKeySecretRef.Name
+}
+
+// tufRootKeys returns the public keys of the PEM keys in the root keys Secret of a Tuf
+func tufRootKeys(ctx context.Context, cli client.Client, namespace, name string) ([]crypto.PublicKey, error) {
+	secret := &corev1.Secret{}
+	if err := cli.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get TUF root keys secret %s/%s: %w", namespace, name, err)
+	}
+	names := make([]string, 0, len(secret.Data))
+	for key := range secret.Data {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+
+	var keys []crypto.PublicKey
+	for _, key := range names {
+		keys = append(keys, clients.ParsePublicKeys(secret.Data[key])...)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no PEM key in TUF root keys secret %s/%s", namespace, name)
+	}
+	return keys, nil
+}
@@ -0,0 +1,153 @@
+package verifier
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/petrpinkas/config-examples/pkg/clients"
+	"github.com/petrpinkas/config-examples/pkg/clients/tuftest"
+	"github.com/theupdateframework/go-tuf/v2/metadata"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newTUFRepository creates a repository publishing the given target files
+func newTUFRepository(targets ...string) *tuftest.Repository {
+	files := map[string][]byte{}
+	for _, name := range targets {
+		files[name] = []byte("content of " + name)
+	}
+	return tuftest.New(files)
+}
+
+var _ = Describe("TUF repository", func() {
+	keys := []string{"rekor.pub", "ctfe.pub", "fulcio_v1.crt.pem", "tsa.certchain.pem"}
+
+	It("should validate the metadata and find every configured key", func(ctx SpecContext) {
+		server := newTUFRepository(keys...).Serve()
+		DeferCleanup(server.Close)
+
+		repo, err := CheckTUFRepository(ctx, server.URL+"/", keys, ProbeOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(repo.URL).To(Equal(server.URL))
+		Expect(repo.Targets).To(Equal([]string{"ctfe.pub", "fulcio_v1.crt.pem", "rekor.pub", "tsa.certchain.pem"}))
+		Expect(repo.Missing).To(BeEmpty())
+		Expect(repo.RootPinned).To(BeFalse())
+		Expect(repo.Versions).To(HaveKeyWithValue(metadata.ROOT, int64(1)))
+		Expect(repo.Expires[metadata.TIMESTAMP]).To(BeTemporally(">", time.Now()))
+		Expect(repo.String()).To(ContainSubstring("4 target(s)"))
+	})
+
+	It("should report configured keys that are not published", func(ctx SpecContext) {
+		server := newTUFRepository("rekor.pub", "ctfe.pub").Serve()
+		DeferCleanup(server.Close)
+
+		repo, err := CheckTUFRepository(ctx, server.URL, keys, ProbeOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(repo.Missing).To(Equal([]string{"fulcio_v1.crt.pem", "tsa.certchain.pem"}))
+	})
+
+	It("should reject expired metadata", func(ctx SpecContext) {
+		repo := newTUFRepository(keys...)
+		repo.Timestamp.Signed.Expires = time.Now().Add(-time.Hour)
+		server := repo.Serve()
+		DeferCleanup(server.Close)
+
+		_, err := CheckTUFRepository(ctx, server.URL, keys, ProbeOptions{})
+		Expect(err).To(MatchError(ContainSubstring("expired")))
+	})
+
+	It("should reject metadata signed with an unknown key", func(ctx SpecContext) {
+		repo := newTUFRepository(keys...)
+		repo.Signers[metadata.TARGETS] = tuftest.NewSigner()
+		server := repo.Serve()
+		DeferCleanup(server.Close)
+
+		_, err := CheckTUFRepository(ctx, server.URL, keys, ProbeOptions{})
+		Expect(err).To(MatchError(ContainSubstring("failed validation")))
+		Expect(err).To(MatchError(ContainSubstring("targets")))
+	})
+
+	It("should fail if root.json is not served", func(ctx SpecContext) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		DeferCleanup(server.Close)
+
+		_, err := CheckTUFRepository(ctx, server.URL, keys, ProbeOptions{})
+		Expect(err).To(MatchError(ContainSubstring("failed to fetch root.json")))
+		Expect(err).To(MatchError(&metadata.ErrDownloadHTTP{StatusCode: http.StatusNotFound}))
+	})
+
+	It("should read the configured keys of a Securesign and a Tuf", func() {
+		securesign := typedFromYAML(`
+apiVersion: rhtas.redhat.com/v1alpha1
+kind: Securesign
+spec:
+  tuf:
+    keys:
+      - name: rekor.pub
+      - name: ctfe.pub
+`)
+		Expect(ExpectedTUFTargets(securesign)).To(Equal([]string{"rekor.pub", "ctfe.pub"}))
+
+		tuf := typedFromYAML(`
+apiVersion: rhtas.redhat.com/v1alpha1
+kind: Tuf
+spec:
+  keys:
+    - name: tsa.certchain.pem
+`)
+		Expect(ExpectedTUFTargets(tuf)).To(Equal([]string{"tsa.certchain.pem"}))
+		Expect(ExpectedTUFTargets(typedFromYAML("apiVersion: rhtas.redhat.com/v1alpha1\nkind: Rekor\n"))).To(BeEmpty())
+	})
+
+	It("should verify the repository published by the Tuf of a Securesign", func(ctx SpecContext) {
+		server := newTUFRepository(keys...).Serve()
+		DeferCleanup(server.Close)
+
+		securesign := newSecuresign("ns", "sample")
+		securesign.SetUID("securesign-uid")
+		tuf := withExternalAccess(owned(tufGVK, "sample", securesign))
+		setField(tuf, server.URL, "status", "url")
+		cli := fake.NewClientBuilder().WithObjects(securesign, tuf).Build()
+
+		rendered := typedFromYAML("apiVersion: rhtas.redhat.com/v1alpha1\nkind: Securesign\nspec:\n  tuf:\n    keys:\n" +
+			"      - name: " + strings.Join(keys, "\n      - name: ") + "\n")
+		VerifyTUFRepository(ctx, cli, "ns", "sample", securesignGVK, rendered, ProbeOptions{})
+	})
+
+	It("should pin root.json to the keys of the root keys secret", func(ctx SpecContext) {
+		repo := newTUFRepository(keys...)
+		server := repo.Serve()
+		DeferCleanup(server.Close)
+
+		securesign := newSecuresign("ns", "sample")
+		securesign.SetUID("securesign-uid")
+		tuf := withExternalAccess(owned(tufGVK, "sample", securesign))
+		setField(tuf, server.URL, "status", "url")
+		rootKeys := secret("tuf-root-keys", map[string]string{"root.pub": string(repo.PublicKeyPEM(metadata.ROOT))})
+		cli := fake.NewClientBuilder().WithObjects(securesign, tuf, rootKeys).Build()
+
+		rendered := typedFromYAML("apiVersion: rhtas.redhat.com/v1alpha1\nkind: Securesign\nspec:\n  tuf:\n" +
+			"    rootKeySecretRef:\n      name: tuf-root-keys\n    keys:\n      - name: rekor.pub\n")
+		VerifyTUFRepository(ctx, cli, "ns", "sample", securesignGVK, rendered, ProbeOptions{})
+
+		keys, err := tufRootKeys(ctx, cli, "ns", "tuf-root-keys")
+		Expect(err).NotTo(HaveOccurred())
+		checked, err := CheckTUFRepository(ctx, server.URL, nil, ProbeOptions{}, clients.WithRootKeys(keys...))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checked.RootPinned).To(BeTrue())
+		Expect(checked.String()).To(ContainSubstring("root v1 (pinned)"))
+
+		other, err := tuftest.NewSigner().PublicKey()
+		Expect(err).NotTo(HaveOccurred())
+		_, err = CheckTUFRepository(ctx, server.URL, nil, ProbeOptions{}, clients.WithRootKeys(other))
+		Expect(err).To(MatchError("none of the 1 root key(s) of root.json is a trusted root key"))
+
+		_, err = tufRootKeys(ctx, fake.NewClientBuilder().WithObjects(secret("tuf-root-keys", map[string]string{"root": "not a key"})).Build(),
+			"ns", "tuf-root-keys")
+		Expect(err).To(MatchError("no PEM key in TUF root keys secret ns/tuf-root-keys"))
+	})
+})
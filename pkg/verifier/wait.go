@@ -0,0 +1,363 @@
+package verifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Default wait settings, long enough for a full Securesign rollout
+const (
+	DefaultTimeout       = 15 * time.Minute
+	DefaultPollInterval  = 2 * time.Second
+	DefaultMaxInterval   = 30 * time.Second
+	DefaultBackoffFactor = 1.5
+)
+
+// ErrWaitTimeout is returned by WaitFor when the condition is not met in time
+var ErrWaitTimeout = errors.New("timed out")
+
+// Ref identifies a resource to wait for
+type Ref struct {
+	GVK       schema.GroupVersionKind
+	Namespace string
+	Name      string
+}
+
+// String returns "Kind namespace/name"
+func (r Ref) String() string {
+	return fmt.Sprintf("%s %s/%s", r.GVK.Kind, r.Namespace, r.Name)
+}
+
+// Condition reports whether a resource reached the desired state
+// obj is never nil; returning an error stops waiting immediately
+type Condition func(obj *unstructured.Unstructured) (bool, error)
+
+// Progress describes an intermediate state observed while waiting
+type Progress struct {
+	Ref     Ref
+	Attempt int
+	Elapsed time.Duration
+	// Object is the last retrieved object, nil if it could not be retrieved
+	Object *unstructured.Unstructured
+	// Err is the error of the last Get, if any
+	Err error
+	// Done is true when the condition is met
+	Done bool
+}
+
+// String returns a one line summary such as "Securesign ns/name: Ready=False (Creating)"
+func (p Progress) String() string {
+	if p.Err != nil {
+		return fmt.Sprintf("%s: %v", p.Ref, p.Err)
+	}
+	return fmt.Sprintf("%s: %s", p.Ref, DescribeConditions(p.Object))
+}
+
+// WaitOptions configures WaitFor
+// Zero values are replaced by the defaults above
+type WaitOptions struct {
+	// Timeout is the total time to wait
+	Timeout time.Duration
+	// PollInterval is the initial interval between two checks when polling
+	PollInterval time.Duration
+	// MaxInterval caps the interval when backing off, and is the resync interval when watching
+	MaxInterval time.Duration
+	// BackoffFactor multiplies the interval after every check, 1 disables backoff
+	BackoffFactor float64
+	// OnProgress is called after every check and every watch event
+	OnProgress func(Progress)
+	// DisableWatch forces polling even if the client supports watches
+	DisableWatch bool
+}
+
+// DefaultWaitOptions returns the default wait settings
+func DefaultWaitOptions() WaitOptions {
+	return WaitOptions{
+		Timeout:       DefaultTimeout,
+		PollInterval:  DefaultPollInterval,
+		MaxInterval:   DefaultMaxInterval,
+		BackoffFactor: DefaultBackoffFactor,
+	}
+}
+
+// Ready is a Condition met when the Ready condition is True
+func Ready(obj *unstructured.Unstructured) (bool, error) {
+	return IsReady(obj), nil
+}
+
+// Exists is a Condition met as soon as the resource exists
+func Exists(_ *unstructured.Unstructured) (bool, error) {
+	return true, nil
+}
+
+// WaitFor waits until condition is met for a resource
+// It does not depend on Ginkgo or Gomega and can be used from CLIs and libraries
+// If the client supports watches (client.WithWatch) the wait is driven by watch events and wakes
+// immediately when the resource changes; otherwise, or when watching is not permitted, it polls with backoff
+// Waiting stops early on non-retryable Get errors (see IsRetryable) and on condition errors
+// On timeout the returned error wraps ErrWaitTimeout and the last Get error, if any
+func WaitFor(ctx context.Context, cli client.Client, ref Ref, condition Condition, opts WaitOptions) error {
+	opts = withDefaults(opts)
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	w := &waiter{ref: ref, condition: condition, opts: opts, start: time.Now()}
+
+	if watchClient, ok := cli.(client.WithWatch); ok && !opts.DisableWatch {
+		err := w.watch(ctx, watchClient)
+		if !errors.Is(err, errWatchUnavailable) {
+			return err
+		}
+		// Watch is not permitted or not supported, fall back to polling
+	}
+	return w.poll(ctx, cli)
+}
+
+// errWatchUnavailable signals that the wait has to fall back to polling
+var errWatchUnavailable = errors.New("watch unavailable")
+
+// maxWatchReconnects is the number of watches in a row that may break without delivering an event
+// before the wait falls back to polling
+const maxWatchReconnects = 5
+
+// waiter holds the state shared by the watch and poll strategies
+type waiter struct {
+	ref       Ref
+	condition Condition
+	opts      WaitOptions
+	start     time.Time
+	attempt   int
+	last      Progress
+}
+
+// observe evaluates the condition for a retrieved object (or Get error) and reports progress
+// It returns done=true when the condition is met and an error when waiting has to stop
+func (w *waiter) observe(obj *unstructured.Unstructured, err error) (bool, error) {
+	w.attempt++
+	w.last = Progress{Ref: w.ref, Attempt: w.attempt, Elapsed: time.Since(w.start), Object: obj, Err: err}
+
+	if err == nil {
+		done, condErr := w.condition(obj)
+		if condErr != nil {
+			return false, fmt.Errorf("condition failed for %s: %w", w.ref, condErr)
+		}
+		w.last.Done = done
+	}
+	if w.opts.OnProgress != nil {
+		w.opts.OnProgress(w.last)
+	}
+	if w.last.Done {
+		return true, nil
+	}
+	if err != nil && !IsRetryable(err) {
+		return false, err
+	}
+	return false, nil
+}
+
+// check retrieves the resource with Get and observes it
+func (w *waiter) check(ctx context.Context, cli client.Client) (bool, error) {
+	obj, err := Get(ctx, cli, w.ref.Namespace, w.ref.Name, w.ref.GVK)
+	return w.observe(obj, err)
+}
+
+// poll checks the resource periodically with backoff
+func (w *waiter) poll(ctx context.Context, cli client.Client) error {
+	return pollWithBackoff(ctx, w.opts,
+		func() (bool, error) { return w.check(ctx, cli) },
+		func(ctxErr error) error { return timeoutError(w.last, ctxErr) })
+}
+
+// pollWithBackoff calls check until it reports done or returns an error that stops waiting
+// The interval between two calls starts at PollInterval and grows by BackoffFactor up to MaxInterval
+// If ctx is done first, the error built by timeout from the context error is returned
+func pollWithBackoff(ctx context.Context, opts WaitOptions, check func() (bool, error), timeout func(ctxErr error) error) error {
+	interval := opts.PollInterval
+	for {
+		done, err := check()
+		if done || err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return timeout(ctx.Err())
+		case <-time.After(interval):
+		}
+		interval = nextInterval(interval, opts)
+	}
+}
+
+// watch checks the resource once and then re-evaluates the condition on every watch event
+// The watch is re-established when the server closes it, after PollInterval with backoff if it broke
+// without delivering an event; the resource is re-read every MaxInterval as a safety net against missed events
+// After maxWatchReconnects broken watches in a row it returns errWatchUnavailable
+func (w *waiter) watch(ctx context.Context, cli client.WithWatch) error {
+	interval := w.opts.PollInterval
+	broken := 0
+	for {
+		// Read the current state first so no change between Get and Watch is missed
+		done, err := w.check(ctx, cli)
+		if done || err != nil {
+			return err
+		}
+
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(w.ref.GVK.GroupVersion().WithKind(w.ref.GVK.Kind + "List"))
+		watcher, err := cli.Watch(ctx, list,
+			client.InNamespace(w.ref.Namespace),
+			client.MatchingFields{"metadata.name": w.ref.Name})
+		if err != nil {
+			if ctx.Err() != nil {
+				return timeoutError(w.last, ctx.Err())
+			}
+			return fmt.Errorf("%w: %w", errWatchUnavailable, err)
+		}
+
+		done, healthy, err := w.consume(ctx, watcher)
+		watcher.Stop()
+		if done || err != nil {
+			return err
+		}
+		if healthy {
+			broken = 0
+			interval = w.opts.PollInterval
+			continue
+		}
+
+		broken++
+		if broken >= maxWatchReconnects {
+			return fmt.Errorf("%w: watch for %s broke %d times in a row", errWatchUnavailable, w.ref, broken)
+		}
+		select {
+		case <-ctx.Done():
+			return timeoutError(w.last, ctx.Err())
+		case <-time.After(interval):
+		}
+		interval = nextInterval(interval, w.opts)
+	}
+}
+
+// consume handles watch events until the condition is met, waiting has to stop,
+// or the watch has to be re-established (done is false and err nil)
+// healthy is false if the watch was closed or failed before it delivered an event
+func (w *waiter) consume(ctx context.Context, watcher watch.Interface) (done, healthy bool, err error) {
+	resync := time.NewTicker(w.opts.MaxInterval)
+	defer resync.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false, healthy, timeoutError(w.last, ctx.Err())
+		case <-resync.C:
+			return false, true, nil
+		case event, ok := <-watcher.ResultChan():
+			if !ok || event.Type == watch.Error {
+				return false, healthy, nil
+			}
+			healthy = true
+			obj, err := toUnstructured(event.Object)
+			if err != nil || obj.GetName() != w.ref.Name {
+				continue // Not our object, the fake client ignores field selectors
+			}
+			if event.Type == watch.Deleted {
+				notFound := apierrors.NewNotFound(schema.GroupResource{Group: w.ref.GVK.Group, Resource: w.ref.GVK.Kind}, w.ref.Name)
+				if _, err := w.observe(nil, newGetError(w.ref.GVK, w.ref.Namespace, w.ref.Name, notFound)); err != nil {
+					return false, healthy, err
+				}
+				continue
+			}
+			obj.SetGroupVersionKind(w.ref.GVK)
+			if done, err := w.observe(obj, nil); done || err != nil {
+				return done, healthy, err
+			}
+		}
+	}
+}
+
+// toUnstructured converts a watch event object to unstructured
+func toUnstructured(obj runtime.Object) (*unstructured.Unstructured, error) {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return u, nil
+	}
+	data, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: data}, nil
+}
+
+// DescribeConditions returns a compact summary of status.conditions, e.g. "Ready=False (Creating)"
+func DescribeConditions(obj *unstructured.Unstructured) string {
+	if obj == nil {
+		return "not found"
+	}
+	conditions, found, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if !found || err != nil || len(conditions) == 0 {
+		return "no conditions"
+	}
+
+	var parts []string
+	for _, cond := range conditions {
+		condMap, ok := cond.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		part := fmt.Sprintf("%v=%v", condMap["type"], condMap["status"])
+		if reason, ok := condMap["reason"].(string); ok && reason != "" {
+			part += " (" + reason + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// timeoutError builds the error returned when waiting did not succeed in time
+func timeoutError(last Progress, ctxErr error) error {
+	if last.Err != nil {
+		return fmt.Errorf("%w after %s waiting for %s: %w", ErrWaitTimeout, last.Elapsed.Round(time.Second), last.Ref, last.Err)
+	}
+	return fmt.Errorf("%w after %s waiting for %s, last state: %s: %w",
+		ErrWaitTimeout, last.Elapsed.Round(time.Second), last.Ref, DescribeConditions(last.Object), ctxErr)
+}
+
+func nextInterval(interval time.Duration, opts WaitOptions) time.Duration {
+	next := time.Duration(float64(interval) * opts.BackoffFactor)
+	if next > opts.MaxInterval {
+		return opts.MaxInterval
+	}
+	return next
+}
+
+func withDefaults(opts WaitOptions) WaitOptions {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultPollInterval
+	}
+	if opts.MaxInterval <= 0 {
+		opts.MaxInterval = DefaultMaxInterval
+	}
+	if opts.MaxInterval < opts.PollInterval {
+		opts.MaxInterval = opts.PollInterval
+	}
+	if opts.BackoffFactor == 0 {
+		opts.BackoffFactor = DefaultBackoffFactor
+	}
+	if opts.BackoffFactor < 1 {
+		opts.BackoffFactor = 1
+	}
+	return opts
+}
@@ -0,0 +1,188 @@
+package verifier
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// fastWait returns wait options suitable for unit tests
+func fastWait(timeout time.Duration) WaitOptions {
+	return WaitOptions{Timeout: timeout, PollInterval: 5 * time.Millisecond, BackoffFactor: 1}
+}
+
+var _ = Describe("WaitFor", func() {
+	var (
+		ctx context.Context
+		ref Ref
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ref = Ref{GVK: securesignGVK, Namespace: "ns", Name: "sample"}
+	})
+
+	It("should wait until the resource appears and becomes ready", func() {
+		var calls atomic.Int32
+		cli := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				n := calls.Add(1)
+				if n < 3 {
+					return apierrors.NewNotFound(schema.GroupResource{Resource: "securesigns"}, key.Name)
+				}
+				status := "False"
+				if n >= 5 {
+					status = "True"
+				}
+				ready := map[string]interface{}{"type": "Ready", "status": status, "reason": "Creating"}
+				newSecuresign(key.Namespace, key.Name, ready).DeepCopyInto(obj.(*unstructured.Unstructured))
+				return nil
+			},
+		}).Build()
+
+		var progress []Progress
+		opts := fastWait(5 * time.Second)
+		opts.DisableWatch = true
+		opts.OnProgress = func(p Progress) { progress = append(progress, p) }
+
+		Expect(WaitFor(ctx, cli, ref, Ready, opts)).To(Succeed())
+		Expect(progress).To(HaveLen(5))
+		Expect(errors.Is(progress[0].Err, ErrNotFound)).To(BeTrue())
+		Expect(progress[2].String()).To(Equal("Securesign ns/sample: Ready=False (Creating)"))
+		Expect(progress[4].Done).To(BeTrue())
+		Expect(progress[4].Attempt).To(Equal(5))
+	})
+
+	It("should time out with the last observed state", func() {
+		notReady := map[string]interface{}{"type": "Ready", "status": "False", "reason": "Failure"}
+		cli := fake.NewClientBuilder().WithObjects(newSecuresign("ns", "sample", notReady)).Build()
+
+		err := WaitFor(ctx, cli, ref, Ready, fastWait(50*time.Millisecond))
+		Expect(errors.Is(err, ErrWaitTimeout)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("last state: Ready=False (Failure)"))
+	})
+
+	It("should time out with the last Get error", func() {
+		cli := failingGetClient(apierrors.NewNotFound(schema.GroupResource{Resource: "securesigns"}, "sample"))
+
+		err := WaitFor(ctx, cli, ref, Exists, fastWait(50*time.Millisecond))
+		Expect(errors.Is(err, ErrWaitTimeout)).To(BeTrue())
+		Expect(errors.Is(err, ErrNotFound)).To(BeTrue())
+	})
+
+	It("should return immediately on non-retryable errors", func() {
+		cli := failingGetClient(apierrors.NewForbidden(schema.GroupResource{Resource: "securesigns"}, "sample", errors.New("RBAC")))
+
+		start := time.Now()
+		err := WaitFor(ctx, cli, ref, Ready, fastWait(time.Minute))
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+		Expect(errors.Is(err, ErrForbidden)).To(BeTrue())
+		Expect(errors.Is(err, ErrWaitTimeout)).To(BeFalse())
+	})
+
+	It("should stop when the condition returns an error", func() {
+		cli := fake.NewClientBuilder().WithObjects(newSecuresign("ns", "sample")).Build()
+		failing := func(*unstructured.Unstructured) (bool, error) { return false, errors.New("broken") }
+
+		err := WaitFor(ctx, cli, ref, failing, fastWait(time.Minute))
+		Expect(err).To(MatchError(ContainSubstring("condition failed for Securesign ns/sample: broken")))
+	})
+
+	It("should wake up on watch events without polling", func() {
+		cli := fake.NewClientBuilder().Build()
+		obj := newSecuresign("ns", "sample")
+
+		go func() {
+			defer GinkgoRecover()
+			time.Sleep(50 * time.Millisecond)
+			Expect(cli.Create(ctx, obj.DeepCopy())).To(Succeed())
+			time.Sleep(50 * time.Millisecond)
+			current, err := Get(ctx, cli, "ns", "sample", securesignGVK)
+			Expect(err).NotTo(HaveOccurred())
+			ready := []interface{}{map[string]interface{}{"type": "Ready", "status": "True"}}
+			Expect(unstructured.SetNestedSlice(current.Object, ready, "status", "conditions")).To(Succeed())
+			Expect(cli.Update(ctx, current)).To(Succeed())
+		}()
+
+		var progress []Progress
+		// Polling and resync intervals are far longer than the test, only watch events can finish it
+		opts := WaitOptions{Timeout: 10 * time.Second, PollInterval: time.Minute, MaxInterval: time.Minute}
+		opts.OnProgress = func(p Progress) { progress = append(progress, p) }
+
+		start := time.Now()
+		Expect(WaitFor(ctx, cli, ref, Ready, opts)).To(Succeed())
+		Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+		Expect(errors.Is(progress[0].Err, ErrNotFound)).To(BeTrue())
+		Expect(progress[len(progress)-1].Done).To(BeTrue())
+	})
+
+	It("should fall back to polling when watch is forbidden", func() {
+		var watched atomic.Bool
+		ready := map[string]interface{}{"type": "Ready", "status": "True"}
+		var gets atomic.Int32
+		cli := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if gets.Add(1) < 3 {
+					return apierrors.NewNotFound(schema.GroupResource{Resource: "securesigns"}, key.Name)
+				}
+				newSecuresign(key.Namespace, key.Name, ready).DeepCopyInto(obj.(*unstructured.Unstructured))
+				return nil
+			},
+			Watch: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) (watch.Interface, error) {
+				watched.Store(true)
+				return nil, apierrors.NewForbidden(schema.GroupResource{Resource: "securesigns"}, "", errors.New("no watch"))
+			},
+		}).Build()
+
+		Expect(WaitFor(ctx, cli, ref, Ready, fastWait(5*time.Second))).To(Succeed())
+		Expect(watched.Load()).To(BeTrue())
+		Expect(gets.Load()).To(BeNumerically(">=", 3))
+	})
+
+	It("should back off and fall back to polling when the watch closes at once", func() {
+		var gets, watches atomic.Int32
+		var getTimes []time.Time
+		cli := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				gets.Add(1)
+				getTimes = append(getTimes, time.Now())
+				return apierrors.NewNotFound(schema.GroupResource{Resource: "securesigns"}, key.Name)
+			},
+			Watch: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) (watch.Interface, error) {
+				watches.Add(1)
+				watcher := watch.NewFake()
+				watcher.Stop()
+				return watcher, nil
+			},
+		}).Build()
+
+		opts := WaitOptions{Timeout: 500 * time.Millisecond, PollInterval: 20 * time.Millisecond, MaxInterval: 50 * time.Millisecond, BackoffFactor: 1.5}
+		err := WaitFor(ctx, cli, ref, Ready, opts)
+		Expect(err).To(MatchError(ErrWaitTimeout))
+		Expect(watches.Load()).To(BeEquivalentTo(maxWatchReconnects))
+		// One Get per watch with backoff in between, then polling at MaxInterval instead of a tight loop
+		Expect(gets.Load()).To(BeNumerically(">", maxWatchReconnects))
+		Expect(gets.Load()).To(BeNumerically("<", 25))
+		for i := 1; i < maxWatchReconnects; i++ {
+			Expect(getTimes[i].Sub(getTimes[i-1])).To(BeNumerically(">=", 20*time.Millisecond))
+		}
+	})
+
+	It("should back off up to the maximum interval", func() {
+		opts := withDefaults(WaitOptions{PollInterval: time.Second, MaxInterval: 3 * time.Second, BackoffFactor: 2})
+		Expect(nextInterval(time.Second, opts)).To(Equal(2 * time.Second))
+		Expect(nextInterval(2*time.Second, opts)).To(Equal(3 * time.Second))
+		Expect(withDefaults(WaitOptions{}).BackoffFactor).To(Equal(DefaultBackoffFactor))
+	})
+})
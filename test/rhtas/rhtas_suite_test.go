@@ -1,13 +1,68 @@
 package rhtas
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/petrpinkas/config-examples/pkg/api"
+	"github.com/petrpinkas/config-examples/pkg/kubernetes"
+	"github.com/petrpinkas/config-examples/pkg/secrets"
+	"github.com/petrpinkas/config-examples/pkg/verifier"
+	"github.com/petrpinkas/config-examples/test/support"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
 
 func TestRhtas(t *testing.T) {
-	RegisterFailHandler(Fail)
+	RegisterFailHandler(support.MaskedFail)
 	RunSpecs(t, "RHTAS Configuration Tests")
 }
+
+// Remove resources left behind by crashed runs when GC_TTL is set (e.g., GC_TTL=24h)
+// and enable diagnostics bundles for failed readiness waits
+// The SETTINGS_FILE and the profile selected by RHTAS_PROFILE are loaded before any client is created
+// Secret references in the values are resolved and masked in all output; in dry runs k8s references resolve to placeholders
+// Invalid configuration values fail the suite early, service URLs are checked when they are used
+var _ = BeforeSuite(func(ctx SpecContext) {
+	restore, err := support.MaskOutput()
+	Expect(err).NotTo(HaveOccurred())
+	DeferCleanup(restore)
+
+	Expect(api.Load(nil)).To(Succeed())
+	if profile := api.SelectedProfile(); profile != nil {
+		fmt.Printf("Using profile %s (context %q)\n", profile.Name, profile.Context)
+	}
+	if support.IsDryRun() {
+		// Nothing is read from the cluster, conf files and templates referencing k8s secrets still render
+		secrets.Register("k8s", secrets.PlaceholderSource("k8s"))
+		Expect(api.ResolveSecrets(ctx)).To(Succeed())
+		settings, err := api.CurrentSettings()
+		Expect(err).NotTo(HaveOccurred())
+		GinkgoWriter.Printf("Settings:\n%s", settings)
+		return
+	}
+
+	clientset, err := kubernetes.GetClientset()
+	Expect(err).NotTo(HaveOccurred())
+	secrets.Register("k8s", secrets.KubernetesSource(clientset.CoreV1()))
+	Expect(api.ResolveSecrets(ctx)).To(Succeed())
+	settings, err := api.CurrentSettings()
+	Expect(err).NotTo(HaveOccurred())
+	GinkgoWriter.Printf("Settings:\n%s", settings)
+
+	diagnostics, err := support.DiagnosticsOptions(clientset.CoreV1())
+	Expect(err).NotTo(HaveOccurred())
+	verifier.SetDiagnostics(diagnostics)
+
+	ttl, enabled, err := support.GCTTL()
+	Expect(err).NotTo(HaveOccurred())
+	if !enabled {
+		return
+	}
+
+	cli, err := kubernetes.GetClient()
+	Expect(err).NotTo(HaveOccurred())
+	fmt.Printf("Collecting garbage from runs older than %s (current run: %s)\n", ttl, support.RunID())
+	Expect(support.CollectGarbage(ctx, cli, ttl)).To(Succeed())
+})
@@ -1,15 +1,20 @@
 package rhtas
 
 import (
+	"errors"
 	"fmt"
+	"maps"
 	"path/filepath"
 
+	"github.com/petrpinkas/config-examples/pkg/api"
+	"github.com/petrpinkas/config-examples/pkg/clients"
 	"github.com/petrpinkas/config-examples/pkg/config"
 	"github.com/petrpinkas/config-examples/pkg/installer"
 	"github.com/petrpinkas/config-examples/pkg/kubernetes"
 	"github.com/petrpinkas/config-examples/pkg/verifier"
 	"github.com/petrpinkas/config-examples/test/support"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -51,6 +56,10 @@ type scenarioTestContext struct {
 	resourceKind     string
 	resourceGVK      schema.GroupVersionKind
 	dryRun           bool
+	serverDryRun     bool
+	dryRunResults    []installer.Result
+	dryRunErr        error
+	expectations     verifier.Expectations
 }
 
 // setupScenario performs all setup steps for a scenario variant
@@ -61,6 +70,7 @@ func setupScenario(ctx SpecContext, folderName, scenarioName, variantName string
 	testCtx := &scenarioTestContext{
 		scenarioName: scenarioName,
 		dryRun:       support.IsDryRun(),
+		serverDryRun: support.IsServerDryRun(),
 	}
 
 	if testCtx.dryRun {
@@ -76,20 +86,37 @@ func setupScenario(ctx SpecContext, folderName, scenarioName, variantName string
 		Expect(testCtx.k8sClient).NotTo(BeNil())
 
 		// Create namespace
-		testCtx.namespace = support.CreateTestNamespace(ctx, testCtx.k8sClient)
+		testCtx.namespace = support.CreateScenarioNamespace(ctx, testCtx.k8sClient, scenarioName, variantName)
 	}
 
 	// Process template with conf file to generate the final YAML
+	// The conf overrides of the selected profile replace values of the conf file, e.g. the OIDC issuer
+	var confOverrides map[string]string
+	if profile := api.SelectedProfile(); profile != nil {
+		confOverrides = profile.Conf
+	}
 	scenariosDir := filepath.Join("..", "..", "scenarios", folderName)
 	var err error
-	testCtx.configPath, err = config.ProcessScenarioTemplate(
+	testCtx.configPath, err = config.ProcessScenarioTemplateWithOverrides(
 		scenarioName,
 		scenariosDir,
 		testCtx.namespace.Name,
 		"securesign-sample",
 		variantName,
+		confOverrides,
 	)
 	Expect(err).NotTo(HaveOccurred(), "Failed to process template")
+
+	// Read expected conditions from the conf file (expect.condition.<Type>=<Status>[/<Reason>]), default is Ready=True
+	confPath := filepath.Join(scenariosDir, scenarioName, fmt.Sprintf("%s-%s-%s.conf", folderName, scenarioName, variantName))
+	confValues, err := config.LoadConfFile(confPath)
+	Expect(err).NotTo(HaveOccurred())
+	maps.Copy(confValues, confOverrides)
+	testCtx.expectations, err = verifier.ParseExpectations(confValues)
+	Expect(err).NotTo(HaveOccurred(), "Invalid expectations in %s", confPath)
+	if testCtx.expectations.IsZero() {
+		testCtx.expectations = verifier.DefaultExpectations()
+	}
 	fmt.Printf("Processing scenario: %s (%s) in namespace: %s\n", scenarioName, testCtx.configPath, testCtx.namespace.Name)
 
 	// Load configuration
@@ -108,22 +135,44 @@ func setupScenario(ctx SpecContext, folderName, scenarioName, variantName string
 
 	if testCtx.dryRun {
 		fmt.Printf("DRY RUN: Would install %s: %s in namespace: %s\n", testCtx.resourceKind, testCtx.securesignName, testCtx.namespace.Name)
+	} else if testCtx.serverDryRun {
+		fmt.Printf("SERVER DRY RUN: Validating %s: %s in namespace: %s\n", testCtx.resourceKind, testCtx.securesignName, testCtx.namespace.Name)
+
+		// Send all documents with dryRun=All; errors are asserted in the validation step
+		testCtx.dryRunResults, testCtx.dryRunErr = installer.ValidateConfig(ctx, testCtx.k8sClient, testCtx.securesignConfig, testCtx.configPath,
+			installer.WithNamespace(testCtx.namespace.Name),
+			installer.WithOwnership(support.Ownership(scenarioName, variantName)))
+		for _, result := range testCtx.dryRunResults {
+			fmt.Printf("SERVER DRY RUN: %s\n", result)
+		}
+
+		// Nothing was persisted except the namespace
+		DeferCleanup(func(ctx SpecContext) {
+			fmt.Printf("Deleting test namespace: %s\n", testCtx.namespace.Name)
+			Expect(testCtx.k8sClient.Delete(ctx, testCtx.namespace)).To(Succeed())
+		})
 	} else {
 		fmt.Printf("Installing %s: %s in namespace: %s\n", testCtx.resourceKind, testCtx.securesignName, testCtx.namespace.Name)
 
 		// Install the configuration (works generically for any Kubernetes resource)
 		// Pass the file path to preserve multi-document YAML structure
-		err = installer.InstallConfig(ctx, testCtx.k8sClient, testCtx.securesignConfig, testCtx.configPath)
+		// Resources must land in the test namespace and are labeled with the run ID
+		// so they can be garbage collected after a crashed run
+		err = installer.InstallConfig(ctx, testCtx.k8sClient, testCtx.securesignConfig, testCtx.configPath,
+			installer.WithNamespace(testCtx.namespace.Name),
+			installer.WithOwnership(support.Ownership(scenarioName, variantName)))
 		Expect(err).NotTo(HaveOccurred())
 		fmt.Printf("%s CR created, waiting for installation...\n", testCtx.resourceKind)
 
 		// Register cleanup: Delete resource first, then namespace
 		DeferCleanup(func(ctx SpecContext) {
 			// Delete resource using GVK from config
-			obj := verifier.Get(ctx, testCtx.k8sClient, testCtx.namespace.Name, testCtx.securesignName, testCtx.resourceGVK)
-			if obj != nil {
+			obj, err := verifier.Get(ctx, testCtx.k8sClient, testCtx.namespace.Name, testCtx.securesignName, testCtx.resourceGVK)
+			if err == nil {
 				fmt.Printf("Deleting %s CR: %s/%s\n", testCtx.resourceKind, testCtx.namespace.Name, testCtx.securesignName)
 				Expect(testCtx.k8sClient.Delete(ctx, obj)).To(Succeed())
+			} else if !errors.Is(err, verifier.ErrNotFound) {
+				fmt.Printf("Failed to get %s CR for deletion: %v\n", testCtx.resourceKind, err)
 			}
 
 			// Delete namespace
@@ -175,26 +224,178 @@ func testScenario(folderName, scenarioName, variantName string) {
 			})
 		})
 
+		Describe("Cluster Validation", func() {
+			It("should pass server-side validation", func() {
+				if !testCtx.serverDryRun {
+					Skip("server-side validation runs only with DRY_RUN=server")
+				}
+				for _, result := range testCtx.dryRunResults {
+					Expect(result.Err).NotTo(HaveOccurred(), "document %d %s/%s failed validation", result.Index, result.Kind, result.Name)
+				}
+				Expect(testCtx.dryRunErr).NotTo(HaveOccurred())
+			})
+		})
+
 		Describe("Resource Installation", func() {
 			It("should install CR successfully", func(ctx SpecContext) {
+				if testCtx.serverDryRun {
+					Skip("nothing is installed with DRY_RUN=server")
+				}
 				if testCtx.dryRun {
 					fmt.Printf("DRY RUN: Skipping CR verification (would check: %s/%s)\n", testCtx.namespace.Name, testCtx.securesignName)
 					return
 				}
 				// Verify the CR exists using GVK from config
-				obj := verifier.Get(ctx, testCtx.k8sClient, testCtx.namespace.Name, testCtx.securesignName, testCtx.resourceGVK)
+				obj, err := verifier.Get(ctx, testCtx.k8sClient, testCtx.namespace.Name, testCtx.securesignName, testCtx.resourceGVK)
+				Expect(err).NotTo(HaveOccurred())
 				Expect(obj).NotTo(BeNil())
 				fmt.Printf("%s CR found: %s/%s\n", testCtx.resourceKind, testCtx.namespace.Name, testCtx.securesignName)
 			})
 
 			It("should wait for resource to be ready", func(ctx SpecContext) {
+				if testCtx.serverDryRun {
+					Skip("nothing is installed with DRY_RUN=server")
+				}
 				if testCtx.dryRun {
 					fmt.Printf("DRY RUN: Skipping readiness verification (would wait for: %s/%s)\n", testCtx.namespace.Name, testCtx.securesignName)
 					return
 				}
-				fmt.Printf("Waiting for %s %s/%s to be ready...\n", testCtx.resourceKind, testCtx.namespace.Name, testCtx.securesignName)
-				verifier.Verify(ctx, testCtx.k8sClient, testCtx.namespace.Name, testCtx.securesignName, testCtx.resourceGVK)
-				fmt.Printf("%s %s/%s is ready!\n", testCtx.resourceKind, testCtx.namespace.Name, testCtx.securesignName)
+				fmt.Printf("Waiting for %s %s/%s to reach %s...\n", testCtx.resourceKind, testCtx.namespace.Name, testCtx.securesignName, testCtx.expectations)
+				verifier.VerifyExpectations(ctx, testCtx.k8sClient, testCtx.namespace.Name, testCtx.securesignName, testCtx.resourceGVK, testCtx.expectations)
+				fmt.Printf("%s %s/%s reached %s!\n", testCtx.resourceKind, testCtx.namespace.Name, testCtx.securesignName, testCtx.expectations)
+			})
+
+			It("should keep the live spec conformant with the rendered config", func(ctx SpecContext) {
+				if testCtx.serverDryRun {
+					Skip("nothing is installed with DRY_RUN=server")
+				}
+				rendered, err := installer.Objects(installer.FileSource(testCtx.configPath))
+				Expect(err).NotTo(HaveOccurred())
+				if testCtx.dryRun {
+					fmt.Printf("DRY RUN: Skipping conformance verification (would compare %d document(s))\n", len(rendered))
+					return
+				}
+				verifier.VerifyConformance(ctx, testCtx.k8sClient, testCtx.namespace.Name, rendered)
+			})
+
+			It("should have all components ready", func(ctx SpecContext) {
+				if testCtx.serverDryRun {
+					Skip("nothing is installed with DRY_RUN=server")
+				}
+				if !verifier.HasComponents(testCtx.resourceGVK) || !testCtx.expectations.ExpectsReady() {
+					Skip(fmt.Sprintf("no component verification for %s expecting %s", testCtx.resourceKind, testCtx.expectations))
+				}
+				if testCtx.dryRun {
+					fmt.Printf("DRY RUN: Skipping component verification (would check components of: %s/%s)\n", testCtx.namespace.Name, testCtx.securesignName)
+					return
+				}
+				verifier.VerifyComponents(ctx, testCtx.k8sClient, testCtx.namespace.Name, testCtx.securesignName, testCtx.resourceGVK)
+				fmt.Printf("All components of %s %s/%s are ready!\n", testCtx.resourceKind, testCtx.namespace.Name, testCtx.securesignName)
+			})
+
+			It("should have component secrets and certificates matching the config", func(ctx SpecContext) {
+				if testCtx.serverDryRun {
+					Skip("nothing is installed with DRY_RUN=server")
+				}
+				if !verifier.HasComponents(testCtx.resourceGVK) || !testCtx.expectations.ExpectsReady() {
+					Skip(fmt.Sprintf("no secret verification for %s expecting %s", testCtx.resourceKind, testCtx.expectations))
+				}
+				rendered := &unstructured.Unstructured{Object: testCtx.securesignConfig.Data}
+				if testCtx.dryRun {
+					fmt.Printf("DRY RUN: Skipping secret verification (would check %d certificate(s))\n", len(verifier.ExpectedCertificates(rendered)))
+					return
+				}
+				verifier.VerifySecrets(ctx, testCtx.k8sClient, testCtx.namespace.Name, testCtx.securesignName, testCtx.resourceGVK)
+				verifier.VerifyCertificates(ctx, testCtx.k8sClient, testCtx.namespace.Name, testCtx.securesignName, testCtx.resourceGVK, rendered)
+			})
+
+			It("should serve the component endpoints", func(ctx SpecContext) {
+				if testCtx.serverDryRun {
+					Skip("nothing is installed with DRY_RUN=server")
+				}
+				if !verifier.HasComponents(testCtx.resourceGVK) || !testCtx.expectations.ExpectsReady() {
+					Skip(fmt.Sprintf("no endpoint verification for %s expecting %s", testCtx.resourceKind, testCtx.expectations))
+				}
+				if testCtx.dryRun {
+					fmt.Printf("DRY RUN: Skipping endpoint verification (would probe endpoints of: %s/%s)\n", testCtx.namespace.Name, testCtx.securesignName)
+					return
+				}
+				verifier.VerifyEndpoints(ctx, testCtx.k8sClient, testCtx.namespace.Name, testCtx.securesignName, testCtx.resourceGVK,
+					verifier.ProbeOptions{InsecureSkipVerify: support.IsInsecureTLS()})
+			})
+
+			It("should publish a valid TUF repository with the configured keys", func(ctx SpecContext) {
+				if testCtx.serverDryRun {
+					Skip("nothing is installed with DRY_RUN=server")
+				}
+				rendered, err := testCtx.securesignConfig.ToTyped()
+				Expect(err).NotTo(HaveOccurred())
+				keys := verifier.ExpectedTUFTargets(rendered)
+				if len(keys) == 0 || !testCtx.expectations.ExpectsReady() {
+					Skip(fmt.Sprintf("no TUF keys configured for %s expecting %s", testCtx.resourceKind, testCtx.expectations))
+				}
+				if testCtx.dryRun {
+					fmt.Printf("DRY RUN: Skipping TUF repository verification (would look for targets: %v)\n", keys)
+					return
+				}
+				verifier.VerifyTUFRepository(ctx, testCtx.k8sClient, testCtx.namespace.Name, testCtx.securesignName, testCtx.resourceGVK,
+					rendered, verifier.ProbeOptions{InsecureSkipVerify: support.IsInsecureTLS()})
+			})
+
+			It("should set up monitoring as configured", func(ctx SpecContext) {
+				if testCtx.serverDryRun {
+					Skip("nothing is installed with DRY_RUN=server")
+				}
+				if !verifier.HasComponents(testCtx.resourceGVK) || !testCtx.expectations.ExpectsReady() {
+					Skip(fmt.Sprintf("no monitoring verification for %s expecting %s", testCtx.resourceKind, testCtx.expectations))
+				}
+				rendered, err := testCtx.securesignConfig.ToTyped()
+				Expect(err).NotTo(HaveOccurred())
+				if testCtx.dryRun {
+					fmt.Printf("DRY RUN: Skipping monitoring verification (expected: %v)\n", verifier.ExpectedMonitoring(rendered))
+					return
+				}
+				clientset, err := kubernetes.GetClientset()
+				Expect(err).NotTo(HaveOccurred())
+				verifier.VerifyMonitoring(ctx, testCtx.k8sClient, testCtx.namespace.Name, testCtx.securesignName, testCtx.resourceGVK,
+					rendered, verifier.ProxyScraper(clientset.CoreV1()))
+			})
+
+			It("should sign and verify a blob against the installed stack", func(ctx SpecContext) {
+				if testCtx.serverDryRun {
+					Skip("nothing is installed with DRY_RUN=server")
+				}
+				if !verifier.HasComponents(testCtx.resourceGVK) || !testCtx.expectations.ExpectsReady() {
+					Skip(fmt.Sprintf("no signing for %s expecting %s", testCtx.resourceKind, testCtx.expectations))
+				}
+				if testCtx.dryRun {
+					fmt.Printf("DRY RUN: Skipping service URL discovery and signing (would read: %s/%s)\n", testCtx.namespace.Name, testCtx.securesignName)
+					return
+				}
+
+				// Discover the URLs of this installation, the values of a previous scenario are replaced
+				name := testCtx.securesignName
+				if testCtx.resourceGVK.Kind != "Securesign" {
+					name = "" // a component CR, its URL is found in the namespace
+				}
+				found, err := verifier.DiscoverServiceURLs(ctx, testCtx.k8sClient, testCtx.namespace.Name, name)
+				Expect(err).NotTo(HaveOccurred())
+				for _, serviceURL := range found {
+					GinkgoWriter.Printf("Discovered %s\n", serviceURL)
+				}
+
+				sigstore, err := clients.NewSigstore()
+				if errors.Is(err, clients.ErrMissingValue) {
+					Skip(fmt.Sprintf("signing needs the service URLs: %v", err))
+				}
+				Expect(err).NotTo(HaveOccurred())
+
+				blob := []byte(fmt.Sprintf("config-examples %s", testCtx.scenarioName))
+				bundle, err := sigstore.SignBlob(ctx, blob)
+				Expect(err).NotTo(HaveOccurred())
+				verification, err := sigstore.VerifyBlob(ctx, blob, bundle, clients.Identity{})
+				Expect(err).NotTo(HaveOccurred())
+				GinkgoWriter.Printf("Verified signature of %s at log index %d\n", verification.Email, verification.LogIndex)
 			})
 		})
 	})
@@ -0,0 +1,62 @@
+package support
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/petrpinkas/config-examples/pkg/verifier"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultOperatorNamespace is the namespace the RHTAS operator is installed to by default
+const DefaultOperatorNamespace = "openshift-rhtas-operator"
+
+// DiagnosticsOptions returns the diagnostics settings configured via environment variables
+//   - DIAGNOSTICS: set to "false" to disable diagnostics bundles (nil is returned)
+//   - ARTIFACT_DIR: directory the bundles are written to (default "artifacts")
+//   - DIAGNOSTICS_TAIL_LINES: log lines collected per container (default 200)
+//   - OPERATOR_NAMESPACE: namespace of the operator, "" skips operator logs (default "openshift-rhtas-operator")
+//   - OPERATOR_SELECTOR: labels of the operator pods, e.g. "control-plane=controller-manager"
+func DiagnosticsOptions(logs corev1client.PodsGetter) (*verifier.DiagnosticsOptions, error) {
+	if value := os.Getenv("DIAGNOSTICS"); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DIAGNOSTICS %q: %w", value, err)
+		}
+		if !enabled {
+			return nil, nil
+		}
+	}
+
+	artifactDir := os.Getenv("ARTIFACT_DIR")
+	if artifactDir == "" {
+		artifactDir = "artifacts"
+	}
+	dir, err := filepath.Abs(artifactDir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ARTIFACT_DIR: %w", err)
+	}
+	opts := &verifier.DiagnosticsOptions{
+		Dir:  dir,
+		Logs: logs,
+	}
+
+	if value := os.Getenv("DIAGNOSTICS_TAIL_LINES"); value != "" {
+		opts.TailLines, err = strconv.ParseInt(value, 10, 64)
+		if err != nil || opts.TailLines <= 0 {
+			return nil, fmt.Errorf("invalid DIAGNOSTICS_TAIL_LINES %q", value)
+		}
+	}
+
+	opts.OperatorNamespace = DefaultOperatorNamespace
+	if value, set := os.LookupEnv("OPERATOR_NAMESPACE"); set {
+		opts.OperatorNamespace = value
+	}
+	if value := os.Getenv("OPERATOR_SELECTOR"); value != "" {
+		opts.OperatorSelector = client.MatchingLabels(parseRunLabels(value))
+	}
+	return opts, nil
+}
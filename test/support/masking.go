@@ -0,0 +1,15 @@
+package support
+
+import (
+	"github.com/onsi/ginkgo/v2"
+	"github.com/petrpinkas/config-examples/pkg/secrets"
+)
+
+// MaskedFail is a Gomega fail handler that masks resolved secrets in failure messages
+func MaskedFail(message string, callerSkip ...int) {
+	skip := 1
+	if len(callerSkip) > 0 {
+		skip += callerSkip[0]
+	}
+	ginkgo.Fail(secrets.Mask(message), skip)
+}
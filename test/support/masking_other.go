@@ -0,0 +1,46 @@
+//go:build !unix
+
+package support
+
+import (
+	"fmt"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/petrpinkas/config-examples/pkg/secrets"
+)
+
+// MaskOutput masks resolved secrets in everything written to GinkgoWriter
+// The descriptors cannot be redirected on this platform, so the Ginkgo report and the output
+// of CLI commands are not masked
+// The returned function flushes pending output and restores GinkgoWriter
+func MaskOutput() (func(), error) {
+	original := ginkgo.GinkgoWriter
+	masked := &maskedGinkgoWriter{GinkgoWriterInterface: original, out: secrets.NewMaskingWriter(original)}
+	ginkgo.GinkgoWriter = masked
+	return func() {
+		_ = masked.out.Flush()
+		ginkgo.GinkgoWriter = original
+	}, nil
+}
+
+// maskedGinkgoWriter masks everything written to the wrapped GinkgoWriter
+type maskedGinkgoWriter struct {
+	ginkgo.GinkgoWriterInterface
+	out *secrets.MaskingWriter
+}
+
+func (w *maskedGinkgoWriter) Write(p []byte) (int, error) {
+	return w.out.Write(p)
+}
+
+func (w *maskedGinkgoWriter) Print(a ...interface{}) {
+	_, _ = fmt.Fprint(w.out, a...)
+}
+
+func (w *maskedGinkgoWriter) Printf(format string, a ...interface{}) {
+	_, _ = fmt.Fprintf(w.out, format, a...)
+}
+
+func (w *maskedGinkgoWriter) Println(a ...interface{}) {
+	_, _ = fmt.Fprintln(w.out, a...)
+}
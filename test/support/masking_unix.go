@@ -0,0 +1,81 @@
+//go:build unix
+
+package support
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/petrpinkas/config-examples/pkg/secrets"
+	"golang.org/x/sys/unix"
+)
+
+// restoreTimeout is how long restoring a descriptor waits for the pending output
+// Child processes that inherited the pipe, e.g. leftover port-forwards, keep it open forever
+const restoreTimeout = 5 * time.Second
+
+// MaskOutput masks resolved secrets in everything the test process writes to stdout and stderr,
+// including the Ginkgo report and the output of CLI commands that inherit the descriptors
+// The returned function flushes pending output and restores the descriptors
+func MaskOutput() (func(), error) {
+	var restores []func()
+	restore := func() {
+		for i := len(restores) - 1; i >= 0; i-- {
+			restores[i]()
+		}
+	}
+	for _, f := range []*os.File{os.Stdout, os.Stderr} {
+		r, err := maskFile(f)
+		if err != nil {
+			restore()
+			return nil, err
+		}
+		restores = append(restores, r)
+	}
+	return restore, nil
+}
+
+// maskFile redirects the descriptor of f through a pipe and copies the masked output to the original descriptor
+func maskFile(f *os.File) (func(), error) {
+	fd := int(f.Fd())
+	saved, err := unix.Dup(fd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to duplicate %s: %w", f.Name(), err)
+	}
+	original := os.NewFile(uintptr(saved), f.Name())
+	r, w, err := os.Pipe()
+	if err != nil {
+		_ = original.Close()
+		return nil, err
+	}
+	if err := unix.Dup2(int(w.Fd()), fd); err != nil {
+		_ = r.Close()
+		_ = w.Close()
+		_ = original.Close()
+		return nil, fmt.Errorf("failed to redirect %s: %w", f.Name(), err)
+	}
+	// fd refers to the pipe now, the reader sees EOF once fd is restored and no child process holds the pipe
+	_ = w.Close()
+
+	out := secrets.NewMaskingWriter(original)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = io.Copy(out, r)
+		_ = out.Flush()
+	}()
+	return func() {
+		_ = unix.Dup2(saved, fd)
+		select {
+		case <-done:
+		case <-time.After(restoreTimeout):
+			// Closing the reader ends the copy, output written later by child processes is lost
+			_ = r.Close()
+			<-done
+		}
+		_ = r.Close()
+		_ = original.Close()
+	}, nil
+}
@@ -13,7 +13,13 @@ import (
 )
 
 // CreateTestNamespace creates a test namespace with a generated name based on the test file
+// The namespace is labeled with the current run ID so it can be garbage collected
 func CreateTestNamespace(ctx ginkgo.SpecContext, cli client.Client) *v1.Namespace {
+	return CreateScenarioNamespace(ctx, cli, "", "")
+}
+
+// CreateScenarioNamespace creates a test namespace labeled with the run ID, scenario and variant
+func CreateScenarioNamespace(ctx ginkgo.SpecContext, cli client.Client, scenarioName, variantName string) *v1.Namespace {
 	sp := ginkgo.CurrentSpecReport()
 	var name string
 	if sp.LeafNodeLocation.FileName != "" {
@@ -30,6 +36,7 @@ func CreateTestNamespace(ctx ginkgo.SpecContext, cli client.Client) *v1.Namespac
 			GenerateName: name + "-",
 		},
 	}
+	Ownership(scenarioName, variantName).Apply(ns)
 	Expect(cli.Create(ctx, ns)).To(Succeed())
 	ginkgo.GinkgoWriter.Printf("Created test namespace: %s\n", ns.Name)
 	return ns
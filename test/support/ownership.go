@@ -0,0 +1,98 @@
+package support
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/petrpinkas/config-examples/pkg/apis/rhtas/v1alpha1"
+	"github.com/petrpinkas/config-examples/pkg/installer"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	runID        string
+	runStartedAt time.Time
+	runOnce      sync.Once
+)
+
+// rhtasKinds lists the RHTAS custom resources searched by CollectGarbage
+var rhtasKinds = []schema.GroupVersionKind{
+	v1alpha1.SecuresignGVK,
+	v1alpha1.FulcioGVK,
+	v1alpha1.RekorGVK,
+	v1alpha1.TrillianGVK,
+	v1alpha1.CTlogGVK,
+	v1alpha1.TimestampAuthorityGVK,
+	v1alpha1.TufGVK,
+}
+
+// RunID returns the ID of the current test run
+// It is taken from the RUN_ID environment variable or generated once per process
+func RunID() string {
+	initRun()
+	return runID
+}
+
+// Ownership returns the run tracking metadata for a scenario variant
+// Additional labels can be configured via RUN_LABELS (e.g., "ci-job=1234,team=qe")
+func Ownership(scenarioName, variantName string) installer.Ownership {
+	initRun()
+	return installer.Ownership{
+		RunID:     runID,
+		Scenario:  scenarioName,
+		Variant:   variantName,
+		CreatedAt: runStartedAt,
+		Labels:    parseRunLabels(os.Getenv("RUN_LABELS")),
+	}
+}
+
+// CollectGarbage deletes namespaces and RHTAS resources left behind by runs older than ttl
+func CollectGarbage(ctx context.Context, cli client.Client, ttl time.Duration) error {
+	deleted, err := installer.CollectGarbage(ctx, cli, ttl, rhtasKinds...)
+	for _, obj := range deleted {
+		fmt.Printf("Garbage collected %s %s (run %s)\n", obj.GetKind(), client.ObjectKeyFromObject(obj), obj.GetLabels()[installer.LabelRunID])
+	}
+	return err
+}
+
+// GCTTL returns the garbage collection TTL configured via GC_TTL (e.g., "24h")
+// The second return value is false if garbage collection is not enabled
+func GCTTL() (time.Duration, bool, error) {
+	value := os.Getenv("GC_TTL")
+	if value == "" {
+		return 0, false, nil
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid GC_TTL %q: %w", value, err)
+	}
+	return ttl, true, nil
+}
+
+func initRun() {
+	runOnce.Do(func() {
+		runStartedAt = time.Now()
+		runID = os.Getenv("RUN_ID")
+		if runID == "" {
+			runID = installer.NewRunID()
+		}
+	})
+}
+
+// parseRunLabels parses comma separated key=value pairs, invalid entries are ignored
+func parseRunLabels(value string) map[string]string {
+	labels := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			continue
+		}
+		labels[parts[0]] = parts[1]
+	}
+	return labels
+}
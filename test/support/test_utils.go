@@ -18,3 +18,16 @@ func CapitalizeFirst(s string) string {
 func IsDryRun() bool {
 	return os.Getenv("DRY_RUN") == "true" || os.Getenv("DRY_RUN") == "1"
 }
+
+// IsServerDryRun checks if server-side dry run mode is enabled via DRY_RUN=server
+// In this mode the scenario is sent to the cluster with dryRun=All, so schema and
+// admission webhook errors are reported without persisting any resource
+func IsServerDryRun() bool {
+	return os.Getenv("DRY_RUN") == "server"
+}
+
+// IsInsecureTLS checks if TLS verification of component endpoints is disabled via INSECURE_SKIP_TLS_VERIFY,
+// e.g. for clusters whose routes use self-signed certificates
+func IsInsecureTLS() bool {
+	return os.Getenv("INSECURE_SKIP_TLS_VERIFY") == "true" || os.Getenv("INSECURE_SKIP_TLS_VERIFY") == "1"
+}